@@ -0,0 +1,57 @@
+package board
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// printColumns prints the column numbers over the cells of a row
+// that start after indent spaces.
+func printColumns(w *bufio.Writer, cols, indent int) {
+	if cols > 9 {
+		// the column numbers take two lines, tens above units
+		w.WriteString(strings.Repeat(" ", indent))
+		for i := 1; i <= cols; i++ {
+			if i < 10 {
+				w.WriteRune(' ')
+			} else {
+				w.WriteRune(rune('0' + i/10))
+			}
+			w.WriteRune(' ')
+		}
+		w.WriteRune('\n')
+	}
+	w.WriteString(strings.Repeat(" ", indent))
+	for i := 1; i <= cols; i++ {
+		w.WriteRune(rune('0' + i%10))
+		w.WriteRune(' ')
+	}
+	w.WriteRune('\n')
+}
+
+// PrintHex prints a board of hexagonal cells to Writer, such as
+// that of Hex.  Each row is drawn half a cell to the right of
+// the row above, so that the board is a rhombus, and the cell
+// (i, j) touches (i-1, j) and (i-1, j+1) above, and (i+1, j-1)
+// and (i+1, j) below.
+func PrintHex(writer io.Writer, b Board) {
+	rows, cols := b.Dim()
+
+	w := bufio.NewWriter(writer)
+	printColumns(w, cols, 3)
+	for i := 0; i < rows; i++ {
+		w.WriteString(strings.Repeat(" ", i))
+		w.WriteRune(rune('A' + i))
+		w.WriteString("  ")
+		for j := 0; j < cols; j++ {
+			w.WriteString(b.Get(i, j))
+			w.WriteRune(' ')
+		}
+		w.WriteRune(' ')
+		w.WriteRune(rune('A' + i))
+		w.WriteRune('\n')
+	}
+	printColumns(w, cols, rows+2)
+	w.Flush()
+}
@@ -0,0 +1,69 @@
+package board
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+)
+
+// Lines is a board of lines between dots, such as that of
+// Dots and Boxes.  Get and Dim are about the boxes between the dots.
+type Lines interface {
+	Board
+	// Line tells if the line from dot (i, j) to the right,
+	// or down if vertical, is drawn.
+	Line(i, j int, vertical bool) bool
+}
+
+// PrintLines prints the dots, the lines drawn, and the boxes,
+// such as their owners, to Writer.  The rows of dots are
+// labelled by letters, and the columns by numbers.
+func PrintLines(writer io.Writer, b Lines) {
+	rows, cols := b.Dim()
+
+	w := bufio.NewWriter(writer)
+	w.WriteString("  ")
+	for j := 0; j <= cols; j++ {
+		n := strconv.Itoa(j + 1)
+		w.WriteString(n)
+		if j < cols {
+			w.WriteString("    "[len(n):])
+		}
+	}
+	w.WriteRune('\n')
+	for i := 0; i <= rows; i++ {
+		w.WriteRune(rune('A' + i))
+		w.WriteRune(' ')
+		for j := 0; j <= cols; j++ {
+			w.WriteRune('.')
+			if j == cols {
+				break
+			}
+			if b.Line(i, j, false) {
+				w.WriteString("---")
+			} else {
+				w.WriteString("   ")
+			}
+		}
+		w.WriteRune('\n')
+		if i == rows {
+			break
+		}
+		w.WriteString("  ")
+		for j := 0; j <= cols; j++ {
+			if b.Line(i, j, true) {
+				w.WriteRune('|')
+			} else {
+				w.WriteRune(' ')
+			}
+			if j == cols {
+				break
+			}
+			w.WriteRune(' ')
+			w.WriteString(b.Get(i, j))
+			w.WriteRune(' ')
+		}
+		w.WriteRune('\n')
+	}
+	w.Flush()
+}
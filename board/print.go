@@ -51,15 +51,24 @@ func printRule(w *bufio.Writer, cols int, boxDrawing []rune) {
 func Print(writer io.Writer, b Board, boxDrawing [][]rune) {
 	rows, cols := b.Dim()
 
-	//BUG: larges boards (more than columns) are not supported yet.
+	w := bufio.NewWriter(writer)
 	if cols > 9 {
-		panic("large Board not supported yet")
+		// the column numbers take two lines, tens above units
+		w.WriteRune(' ')
+		for i := 1; i <= cols; i++ {
+			w.WriteRune(' ')
+			if i < 10 {
+				w.WriteRune(' ')
+			} else {
+				w.WriteRune(rune('0' + i/10))
+			}
+		}
+		w.WriteRune('\n')
 	}
-	w := bufio.NewWriter(writer)
 	w.WriteRune(' ')
-	for i := 0; i < cols; i++ {
+	for i := 1; i <= cols; i++ {
 		w.WriteRune(' ')
-		w.WriteRune(rune('1' + i))
+		w.WriteRune(rune('0' + i%10))
 	}
 	w.WriteRune('\n')
 
@@ -0,0 +1,57 @@
+package checkers
+
+import "math/rand"
+
+// Zobrist keys for hashing a state.
+var (
+	zobristCell  [N][N][4]uint64       // for a man and a king of O and X at each square
+	zobristX     uint64                // for X's turn
+	zobristQuiet [DrawPlies + 1]uint64 // for the plies of Quiet, up to DrawPlies
+)
+
+// Zobrist keys generation
+func init() {
+	r := rand.New(rand.NewSource(N))
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			for k := range zobristCell[i][j] {
+				zobristCell[i][j][k] = r.Uint64()
+			}
+		}
+	}
+	zobristX = r.Uint64()
+	for k := range zobristQuiet {
+		zobristQuiet[k] = r.Uint64()
+	}
+}
+
+// zobristIndex returns the index of the piece in the Zobrist keys.
+func zobristIndex(c Cell) int {
+	k := int(c.Player() - 1)
+	if c&King != 0 {
+		k += 2
+	}
+	return k
+}
+
+// Hash returns the Zobrist hash of the state, which tells Quiet
+// as well, since the moves and the evaluation depend on how close
+// the state is to a draw.
+func (s *State) Hash() (h uint64) {
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			if c := s.Board[i][j]; c != Empty {
+				h ^= zobristCell[i][j][zobristIndex(c)]
+			}
+		}
+	}
+	if s.Turn == X {
+		h ^= zobristX
+	}
+	if q := s.Quiet; q < DrawPlies {
+		h ^= zobristQuiet[q]
+	} else {
+		h ^= zobristQuiet[DrawPlies]
+	}
+	return
+}
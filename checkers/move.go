@@ -0,0 +1,80 @@
+package checkers
+
+import (
+	"errors"
+	"strings"
+)
+
+// Square represents a square on the board.
+type Square struct {
+	I, J uint8
+}
+
+// String converts a Square to the string representation.
+func (q Square) String() string {
+	return string([]byte{byte(q.I) + 'A', byte(q.J) + '1'})
+}
+
+// Move is the path of the piece moved: the square it starts from,
+// followed by the square of each step or jump.
+type Move []Square
+
+// IsCapture tells if the move jumps over pieces.
+func (m Move) IsCapture() bool {
+	return len(m) >= 2 && (m[0].I-m[1].I == 2 || m[1].I-m[0].I == 2)
+}
+
+// Equal tells if two moves go along the same path.
+func (m Move) Equal(n Move) bool {
+	if len(m) != len(n) {
+		return false
+	}
+	for k := range m {
+		if m[k] != n[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// String converts a Move to the string representation,
+// such as "C3-D4" for a step, or "C3xE5xG7" for a capture.
+func (m Move) String() string {
+	sep := "-"
+	if m.IsCapture() {
+		sep = "x"
+	}
+	s := make([]string, len(m))
+	for k, q := range m {
+		s[k] = q.String()
+	}
+	return strings.Join(s, sep)
+}
+
+// ParseMove converts the string representation of a move back to
+// a Move.  It does not tell if the move is allowed.
+func ParseMove(str string) (Move, error) {
+	str = strings.ToUpper(str)
+	var m Move
+	for _, f := range strings.FieldsFunc(str, func(r rune) bool { return r == '-' || r == 'X' }) {
+		if len(f) != 2 || f[0] < 'A' || f[0] >= 'A'+N || f[1] < '1' || f[1] >= '1'+N {
+			return nil, errors.New("checkers: bad move " + str)
+		}
+		m = append(m, Square{f[0] - 'A', f[1] - '1'})
+	}
+	if len(m) < 2 {
+		return nil, errors.New("checkers: bad move " + str)
+	}
+	return m, nil
+}
+
+// MoveKey returns the squares where the last move started and ended,
+// as a number.
+func (s *State) MoveKey() int {
+	m := s.LastMove
+	if len(m) == 0 {
+		return 0
+	}
+	from, to := m[0], m[len(m)-1]
+	return (int(from.I)*N+int(from.J))*N*N + int(to.I)*N + int(to.J)
+}
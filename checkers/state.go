@@ -0,0 +1,288 @@
+// Package checkers implements English draughts on the 8x8 board.
+//
+// The pieces stand on the squares (i, j) with i+j even.  O starts on
+// the rows A to C and moves first, towards the row H; X starts on the
+// rows F to H, and moves towards the row A.  Men move one square
+// diagonally forward, and kings in any diagonal direction.
+// Capturing is compulsory, and a capture must go on as long as the
+// capturing piece can jump again, except that a man reaching the
+// far row is crowned and the move ends there.  A player who cannot
+// move loses, and the game is drawn when 40 moves by each player
+// pass without a capture or a man moving.
+package checkers
+
+import "github.com/z-rui/game"
+
+// N is the board size.
+const N = 8
+
+// Cell represents a square of the board: Empty,
+// or a man or a king of O or X.
+type Cell uint8
+
+const (
+	Empty Cell = 0
+	O     Cell = 1
+	X     Cell = 2
+	King  Cell = 4 // added to O or X for a king
+)
+
+// String converts a cell to the string representation:
+// lower case for a man, and upper case for a king.
+func (c Cell) String() string {
+	switch c {
+	case O:
+		return "o"
+	case X:
+		return "x"
+	case O | King:
+		return "O"
+	case X | King:
+		return "X"
+	default:
+		return " "
+	}
+}
+
+// Player returns O or X, the owner of the piece, or Empty.
+func (c Cell) Player() Cell {
+	return c & (O | X)
+}
+
+// DrawPlies is the number of plies without a capture or a man moving
+// after which the game is drawn.
+const DrawPlies = 80
+
+// State represents the current state of the game.
+type State struct {
+	Board    [N][N]Cell
+	LastMove Move
+	Turn     Cell // must be O or X
+	Quiet    int  // plies since the last capture or man move
+}
+
+// NewState returns a new state at the start of the game.
+func NewState() *State {
+	s := new(State)
+	for i := 0; i < 3; i++ {
+		for j := i % 2; j < N; j += 2 {
+			s.Board[i][j] = O
+			s.Board[N-1-i][N-1-j] = X
+		}
+	}
+	s.Turn = O
+	return s
+}
+
+// Dim returns the dimension of the board
+func (s *State) Dim() (int, int) {
+	return N, N
+}
+
+// Get returns the string representation at (i, j)
+func (s *State) Get(i, j int) string {
+	return s.Board[i][j].String()
+}
+
+// forward returns the direction of the rows in which
+// the men of the player move.
+func forward(player Cell) int {
+	if player == O {
+		return 1
+	}
+	return -1
+}
+
+// crowned tells if a man of the player is crowned on the row i.
+func crowned(player Cell, i int) bool {
+	if player == O {
+		return i == N-1
+	}
+	return i == 0
+}
+
+// inside tells if (i, j) is on the board.
+func inside(i, j int) bool {
+	return 0 <= i && i < N && 0 <= j && j < N
+}
+
+// captured marks a piece jumped over in the move being generated,
+// which cannot be jumped again.
+const captured Cell = 8
+
+// Moves returns all the moves allowed in the current state:
+// the captures if there is any, and the other moves otherwise.
+func (s *State) Moves() (moves []Move) {
+	if s.Quiet >= DrawPlies {
+		return nil
+	}
+	b := s.Board
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			if b[i][j].Player() == s.Turn {
+				piece := b[i][j]
+				b[i][j] = Empty
+				s.jumps(&b, piece, Move{{uint8(i), uint8(j)}}, &moves)
+				b[i][j] = piece
+			}
+		}
+	}
+	if len(moves) != 0 {
+		return
+	}
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			piece := b[i][j]
+			if piece.Player() != s.Turn {
+				continue
+			}
+			for _, d := range directions(piece) {
+				ti, tj := i+d[0], j+d[1]
+				if inside(ti, tj) && b[ti][tj] == Empty {
+					moves = append(moves, Move{{uint8(i), uint8(j)}, {uint8(ti), uint8(tj)}})
+				}
+			}
+		}
+	}
+	return
+}
+
+// directions returns the directions in which the piece moves.
+func directions(piece Cell) [][2]int {
+	f := forward(piece.Player())
+	if piece&King != 0 {
+		return [][2]int{{f, -1}, {f, 1}, {-f, -1}, {-f, 1}}
+	}
+	return [][2]int{{f, -1}, {f, 1}}
+}
+
+// jumps adds to moves the captures by the piece, which has gone
+// along path, on the board b where the pieces captured are marked.
+func (s *State) jumps(b *[N][N]Cell, piece Cell, path Move, moves *[]Move) {
+	last := path[len(path)-1]
+	i, j := int(last.I), int(last.J)
+	found := false
+	for _, d := range directions(piece) {
+		mi, mj := i+d[0], j+d[1]
+		ti, tj := i+2*d[0], j+2*d[1]
+		if !inside(ti, tj) || b[ti][tj] != Empty {
+			continue
+		}
+		if c := b[mi][mj]; c&captured != 0 || c.Player() != s.Turn^(O^X) {
+			continue
+		}
+		found = true
+		b[mi][mj] |= captured
+		next := append(path[:len(path):len(path)], Square{uint8(ti), uint8(tj)})
+		if piece&King == 0 && crowned(piece, ti) {
+			*moves = append(*moves, next)
+		} else {
+			s.jumps(b, piece, next, moves)
+		}
+		b[mi][mj] &^= captured
+	}
+	if !found && len(path) > 1 {
+		*moves = append(*moves, path)
+	}
+}
+
+// Play returns the next state after the move,
+// which must be one returned by Moves.
+func (s *State) Play(m Move) *State {
+	t := *s
+	from, to := m[0], m[len(m)-1]
+	piece := t.Board[from.I][from.J]
+	t.Board[from.I][from.J] = Empty
+	if m.IsCapture() {
+		for k := 1; k < len(m); k++ {
+			t.Board[(m[k-1].I+m[k].I)/2][(m[k-1].J+m[k].J)/2] = Empty
+		}
+	}
+	if piece&King == 0 && crowned(piece, int(to.I)) {
+		piece |= King
+	}
+	t.Board[to.I][to.J] = piece
+	if m.IsCapture() || s.Board[from.I][from.J]&King == 0 {
+		t.Quiet = 0
+	} else {
+		t.Quiet++
+	}
+	t.LastMove = m
+	t.Turn ^= O ^ X
+	return &t
+}
+
+// Move returns the next state after the move, which is matched
+// against the moves allowed.  It returns nil if the move is not allowed.
+func (s *State) Move(m Move) *State {
+	for _, mv := range s.Moves() {
+		if mv.Equal(m) {
+			return s.Play(mv)
+		}
+	}
+	return nil
+}
+
+// Next returns all possible next states.
+func (s *State) Next() (nxt []game.State) {
+	moves := s.Moves()
+	nxt = make([]game.State, len(moves))
+	for k, m := range moves {
+		nxt[k] = s.Play(m)
+	}
+	return
+}
+
+// IsEnd tells if the game has ended.
+func (s *State) IsEnd() bool {
+	return len(s.Moves()) == 0
+}
+
+// Weights of the evaluation.
+const (
+	manValue     = 100
+	kingValue    = 160
+	advanceValue = 3 // of each row a man has advanced
+	backValue    = 5 // of a man guarding the row where the opponent is crowned
+)
+
+// Eval returns the evaluation of the current state.
+// A player who cannot move has lost; otherwise the evaluation
+// counts the material, and how far the men have advanced.
+func (s *State) Eval() (eval game.Evaluation) {
+	if s.Quiet >= DrawPlies {
+		return 0
+	}
+	if s.IsEnd() {
+		if s.Turn == O {
+			return game.Lost
+		}
+		return game.Won
+	}
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			c := s.Board[i][j]
+			if c == Empty {
+				continue
+			}
+			var v game.Evaluation = kingValue
+			if c&King == 0 {
+				// rows advanced from the start
+				adv := i
+				if c == X {
+					adv = N - 1 - i
+				}
+				v = manValue + advanceValue*game.Evaluation(adv)
+				if adv == 0 {
+					v += backValue
+				}
+			}
+			if c.Player() == O {
+				eval += v
+			} else {
+				eval -= v
+			}
+		}
+	}
+	return
+}
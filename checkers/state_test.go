@@ -0,0 +1,165 @@
+package checkers
+
+import (
+	"context"
+	"github.com/z-rui/game"
+	"testing"
+)
+
+// parse parses the moves, failing the test on an error.
+func parse(t *testing.T, str string) Move {
+	m, err := ParseMove(str)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// empty returns a state with an empty board, and O to move.
+func empty() *State {
+	return &State{Turn: O}
+}
+
+func TestPerft(t *testing.T) {
+	for depth, want := range []uint64{1, 7, 49, 302, 1469, 7361, 36768, 179740} {
+		if got := game.Perft(NewState(), uint(depth)); got != want {
+			t.Errorf("depth %d: got %d, want %d", depth, got, want)
+		}
+	}
+}
+
+func TestMultiJump(t *testing.T) {
+	s := empty()
+	s.Board[2][2] = O
+	s.Board[3][3] = X
+	s.Board[5][5] = X
+	s.Board[5][3] = X // a second jump that ends earlier
+	s.Board[0][0] = X // far away, does not matter
+	s.Board[7][7] = X
+	moves := s.Moves()
+	if len(moves) != 2 {
+		t.Fatalf("got moves %v, want 2 captures", moves)
+	}
+	want := parse(t, "C3xE5xG7")
+	t2 := s.Move(want)
+	if t2 == nil {
+		t.Fatalf("%v not allowed: %v", want, moves)
+	}
+	if t2.Board[3][3] != Empty || t2.Board[5][5] != Empty || t2.Board[5][3] != X || t2.Board[6][6] != O {
+		t.Errorf("wrong board after %v", want)
+	}
+	if s.Move(parse(t, "C3xE5")) != nil {
+		t.Errorf("capture stopped while the piece can jump again")
+	}
+	if got := want.String(); got != "C3xE5xG7" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestForcedCapture(t *testing.T) {
+	s := empty()
+	s.Board[2][2] = O
+	s.Board[2][6] = O
+	s.Board[3][5] = X
+	for _, m := range s.Moves() {
+		if !m.IsCapture() {
+			t.Errorf("step %v allowed while a capture is possible", m)
+		}
+	}
+	if s.Move(parse(t, "C3-D4")) != nil {
+		t.Errorf("C3-D4 allowed while a capture is possible")
+	}
+}
+
+func TestPromotion(t *testing.T) {
+	s := empty()
+	s.Board[5][1] = O
+	s.Board[6][2] = X
+	s.Board[6][4] = X // a king could jump on from H4, but a man stops
+	s.Board[2][2] = X
+	u := s.Move(parse(t, "F2xH4"))
+	if u == nil {
+		t.Fatalf("F2xH4 not allowed: %v", s.Moves())
+	}
+	if u.Board[7][3] != O|King {
+		t.Errorf("man not crowned")
+	}
+	u.Turn = O
+	if u.Move(parse(t, "H4-G5")) != nil {
+		t.Errorf("king may not jump over the piece next to it")
+	}
+	if u.Move(parse(t, "H4xF6")) == nil {
+		t.Errorf("king cannot capture backwards: %v", u.Moves())
+	}
+}
+
+func TestEnd(t *testing.T) {
+	s := empty()
+	s.Board[0][0] = O
+	s.Board[1][1] = X
+	s.Board[2][2] = X
+	if !s.IsEnd() || s.Eval() != game.Lost {
+		t.Errorf("blocked O has not lost: %v", s.Moves())
+	}
+
+	s = empty()
+	s.Board[4][4] = O | King
+	s.Board[0][0] = X | King
+	s.Quiet = DrawPlies - 1
+	u := s.Move(parse(t, "E5-F6"))
+	if u == nil || !u.IsEnd() || u.Eval() != 0 {
+		t.Errorf("no draw after %d quiet plies", DrawPlies)
+	}
+	s.Board[4][4] = O
+	if u := s.Move(parse(t, "E5-F6")); u.Quiet != 0 {
+		t.Errorf("man move does not reset the count")
+	}
+}
+
+func TestHashQuiet(t *testing.T) {
+	s := NewState()
+	u := *s
+	u.Quiet = 1
+	if s.Hash() == u.Hash() {
+		t.Errorf("states differing in Quiet hashed the same")
+	}
+	u.Quiet = DrawPlies - 1
+	v := u
+	v.Quiet = DrawPlies
+	if u.Hash() == v.Hash() {
+		t.Errorf("drawn state hashed as the one before")
+	}
+	u.Quiet = DrawPlies + 1
+	if u.Hash() != v.Hash() {
+		t.Errorf("drawn states hashed differently")
+	}
+}
+
+func TestParseMove(t *testing.T) {
+	for _, str := range []string{"C3", "C3-", "I1-H2", "C9-D8"} {
+		if _, err := ParseMove(str); err == nil {
+			t.Errorf("%q parsed", str)
+		}
+	}
+	if m := parse(t, "c3-d4"); m.String() != "C3-D4" {
+		t.Errorf("got %v", m)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	// O can win a piece: the X man must be captured
+	s := empty()
+	s.Board[2][2] = O
+	s.Board[2][4] = O
+	s.Board[5][5] = X
+	s.Board[6][6] = X
+	opt := game.Options{MaxDepth: 8, Table: game.NewTable(16), PVS: true, Killers: true, History: true}
+	r, _ := game.SearchOf(context.Background(), s, false, opt)
+	if r.Next == nil || r.Eval <= 0 {
+		t.Errorf("O not better: %v", r.Eval)
+	}
+	r, _ = game.SearchOf(context.Background(), NewState(), false, opt)
+	if r.Next == nil || len(r.PV) == 0 {
+		t.Errorf("no move at the start")
+	}
+}
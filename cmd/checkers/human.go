@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"github.com/z-rui/game/checkers"
+	"log"
+	"strings"
+	"unicode"
+)
+
+type HumanPlayer struct {
+	name string
+}
+
+func (p *HumanPlayer) Name() string {
+	return p.name
+}
+
+func askPlaying() checkers.Cell {
+	for {
+		fmt.Print("Do you want to play as O or X? ")
+		answer, err := stdin.ReadString('\n')
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if err == nil && len(answer) >= 2 {
+			switch unicode.ToUpper(rune(answer[0])) {
+			case 'O':
+				return checkers.O
+			case 'X':
+				return checkers.X
+			}
+		}
+		fmt.Println("Sorry, but that does not make sense.")
+	}
+}
+
+func (p *HumanPlayer) Next(s *checkers.State) (t *checkers.State) {
+	if s.IsEnd() {
+		return nil
+	}
+	for {
+		fmt.Print("Which move do you want to make (e.g. C3-D4, C3xE5)? ")
+		str, err := stdin.ReadString('\n')
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if m, err := checkers.ParseMove(strings.TrimSpace(str)); err == nil {
+			t = s.Move(m)
+			if t != nil {
+				return
+			}
+		}
+		fmt.Println("Sorry, but that does not make sense.")
+		fmt.Println("Allowed moves:", s.Moves())
+	}
+}
@@ -0,0 +1,101 @@
+// Command checkers is a console-based program to play the Checkers (English draughts) game.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"github.com/z-rui/game"
+	"github.com/z-rui/game/board"
+	"github.com/z-rui/game/checkers"
+	"os"
+	"runtime/pprof"
+	"time"
+)
+
+var (
+	cpuLevel      = flag.Uint("L", 8, "CPU Level: 1(weakest)...12(strongest)")
+	demoMode      = flag.Bool("a", false, "Two Cpus play with each other")
+	cpuTime       = flag.Float64("t", 0, "Seconds per CPU move (overrides -L)")
+	mctsMode      = flag.Bool("m", false, "Cpu uses Monte Carlo tree search")
+	mctsPlayouts  = flag.Int("n", 10000, "Playouts per move for Monte Carlo tree search")
+	verboseSearch = flag.Bool("v", false, "Show Cpu decision details")
+	boxChars      = flag.Bool("U", false, "Use box-drawing characters")
+	cpuProfile    = flag.String("p", "", "Write cpu profile to file")
+)
+
+var (
+	stdin = bufio.NewReader(os.Stdin)
+)
+
+type Player interface {
+	Next(s *checkers.State) *checkers.State
+	Name() string
+}
+
+func main() {
+	flag.Parse()
+	if *cpuLevel < 1 {
+		*cpuLevel = 1
+	}
+	if *cpuProfile != "" {
+		f, err := os.Create(*cpuProfile)
+		if err != nil {
+			panic(err)
+		}
+		pprof.StartCPUProfile(f)
+		defer pprof.StopCPUProfile()
+	}
+
+	budget := time.Duration(*cpuTime * float64(time.Second))
+	newCpuPlayer := func(name string) Player {
+		if *mctsMode {
+			m := &game.MCTS{Iterations: *mctsPlayouts, Budget: budget}
+			if budget > 0 {
+				m.Iterations = 0
+			}
+			return &MctsPlayer{name, m}
+		}
+		return &CpuPlayer{name, *cpuLevel, budget, game.NewTable(tableBits)}
+	}
+
+	var p [2]Player
+	if *demoMode {
+		p[0] = newCpuPlayer("CPU 1")
+		p[1] = newCpuPlayer("CPU 2")
+	} else {
+		p[0] = &HumanPlayer{"You"}
+		p[1] = newCpuPlayer("CPU")
+		if askPlaying() == checkers.X {
+			p[0], p[1] = p[1], p[0]
+		}
+	}
+
+	s := checkers.NewState()
+	i := 0
+	for {
+		if *boxChars {
+			board.Print(os.Stdout, s, board.UnicodeBox)
+		} else {
+			board.Print(os.Stdout, s, board.AsciiBox)
+		}
+		if t := p[i].Next(s); t == nil {
+			break
+		} else {
+			s = t
+		}
+		who := p[i].Name()
+		fmt.Println(who, "moved", s.LastMove)
+		i ^= 1
+	}
+
+	fmt.Print("Game over.  ")
+	switch s.Eval() {
+	case game.Won:
+		fmt.Println(p[0].Name(), "won")
+	case game.Lost:
+		fmt.Println(p[1].Name(), "won")
+	default:
+		fmt.Println("It was a draw")
+	}
+}
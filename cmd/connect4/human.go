@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"github.com/z-rui/game/connect4"
+	"log"
+	"strings"
+	"unicode"
+)
+
+type HumanPlayer struct {
+	name string
+}
+
+func (p *HumanPlayer) Name() string {
+	return p.name
+}
+
+func askPlaying() connect4.Cell {
+	for {
+		fmt.Print("Do you want to play as O or X? ")
+		answer, err := stdin.ReadString('\n')
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if err == nil && len(answer) >= 2 {
+			switch unicode.ToUpper(rune(answer[0])) {
+			case 'O':
+				return connect4.O
+			case 'X':
+				return connect4.X
+			}
+		}
+		fmt.Println("Sorry, but that does not make sense.")
+	}
+}
+
+func (p *HumanPlayer) Next(s *connect4.State) (t *connect4.State) {
+	if s.IsEnd() {
+		return nil
+	}
+	for {
+		fmt.Print("Which column do you want to drop into? ")
+		col, err := stdin.ReadString('\n')
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if m, err := connect4.ParseMove(strings.TrimSpace(col)); err == nil {
+			t = s.Move(m)
+			if t != nil {
+				return
+			}
+		}
+		fmt.Println("Sorry, but that does not make sense.")
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/z-rui/game"
+	"github.com/z-rui/game/dotsboxes"
+	"time"
+)
+
+// tableBits is the size of the transposition table used by CpuPlayer.
+const tableBits = 20
+
+type CpuPlayer struct {
+	name   string
+	level  uint
+	budget time.Duration // if non-zero, search by time instead of level
+	table  *game.Table
+}
+
+func (p *CpuPlayer) Name() string {
+	return p.name
+}
+
+func (p *CpuPlayer) Next(s *dotsboxes.State) *dotsboxes.State {
+	opt := game.Options{
+		Budget:  p.budget,
+		Table:   p.table,
+		PVS:     true,
+		Killers: true,
+		History: true,
+	}
+	if p.budget == 0 {
+		opt.MaxDepth = p.level
+	}
+	r, _ := game.SearchOf(context.Background(), s, s.Turn == dotsboxes.X, opt)
+	if *verboseSearch {
+		printResult(r)
+	}
+	return r.Next
+}
+
+type MctsPlayer struct {
+	name   string
+	engine *game.MCTS
+}
+
+func (p *MctsPlayer) Name() string {
+	return p.name
+}
+
+func (p *MctsPlayer) Next(s *dotsboxes.State) *dotsboxes.State {
+	res, _ := p.engine.Search(context.Background(), s, s.Turn == dotsboxes.X)
+	r := game.ResultOf[*dotsboxes.State](res)
+	if *verboseSearch {
+		printResult(r)
+	}
+	return r.Next
+}
+
+// printResult prints the details of a search.
+func printResult(r *game.TypedResult[*dotsboxes.State]) {
+	fmt.Printf("Depth %d: value = %v, PV:", r.Depth, r.Eval)
+	for _, t := range r.PV {
+		fmt.Print(" ", t.LastMove)
+	}
+	fmt.Println()
+	fmt.Printf("%d nodes, %d cutoffs in %v (%.0f nodes/s)\n",
+		r.Nodes, r.Cutoffs, r.Elapsed, r.NPS())
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"github.com/z-rui/game/dotsboxes"
+	"log"
+	"strings"
+	"unicode"
+)
+
+type HumanPlayer struct {
+	name string
+}
+
+func (p *HumanPlayer) Name() string {
+	return p.name
+}
+
+func askPlaying() dotsboxes.Cell {
+	for {
+		fmt.Print("Do you want to play as O or X? ")
+		answer, err := stdin.ReadString('\n')
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if err == nil && len(answer) >= 2 {
+			switch unicode.ToUpper(rune(answer[0])) {
+			case 'O':
+				return dotsboxes.O
+			case 'X':
+				return dotsboxes.X
+			}
+		}
+		fmt.Println("Sorry, but that does not make sense.")
+	}
+}
+
+func (p *HumanPlayer) Next(s *dotsboxes.State) (t *dotsboxes.State) {
+	if s.IsEnd() {
+		return nil
+	}
+	for {
+		fmt.Print("Which line do you want to draw (e.g. A1-A2)? ")
+		line, err := stdin.ReadString('\n')
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if m, err := dotsboxes.ParseMove(strings.TrimSpace(line)); err == nil {
+			t = s.Move(m)
+			if t != nil {
+				return
+			}
+		}
+		fmt.Println("Sorry, but that does not make sense.")
+	}
+}
@@ -0,0 +1,109 @@
+// Command dotsboxes is a console-based program to play the Dots and Boxes game.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"github.com/z-rui/game"
+	"github.com/z-rui/game/board"
+	"github.com/z-rui/game/dotsboxes"
+	"log"
+	"os"
+	"runtime/pprof"
+	"time"
+)
+
+var (
+	rows          = flag.Int("rows", dotsboxes.Standard.Rows, "Number of rows of boxes")
+	cols          = flag.Int("cols", dotsboxes.Standard.Cols, "Number of columns of boxes")
+	cpuLevel      = flag.Uint("L", 8, "CPU Level: 1(weakest)...16(strongest)")
+	demoMode      = flag.Bool("a", false, "Two Cpus play with each other")
+	cpuTime       = flag.Float64("t", 0, "Seconds per CPU move (overrides -L)")
+	mctsMode      = flag.Bool("m", false, "Cpu uses Monte Carlo tree search")
+	mctsPlayouts  = flag.Int("n", 10000, "Playouts per move for Monte Carlo tree search")
+	verboseSearch = flag.Bool("v", false, "Show Cpu decision details")
+	cpuProfile    = flag.String("p", "", "Write cpu profile to file")
+)
+
+var (
+	stdin = bufio.NewReader(os.Stdin)
+)
+
+type Player interface {
+	Next(s *dotsboxes.State) *dotsboxes.State
+	Name() string
+}
+
+func main() {
+	flag.Parse()
+	if *cpuLevel < 1 {
+		*cpuLevel = 1
+	}
+	s, err := dotsboxes.Game{Rows: *rows, Cols: *cols}.NewState()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if *cpuProfile != "" {
+		f, err := os.Create(*cpuProfile)
+		if err != nil {
+			panic(err)
+		}
+		pprof.StartCPUProfile(f)
+		defer pprof.StopCPUProfile()
+	}
+
+	budget := time.Duration(*cpuTime * float64(time.Second))
+	newCpuPlayer := func(name string) Player {
+		if *mctsMode {
+			m := &game.MCTS{Iterations: *mctsPlayouts, Budget: budget}
+			if budget > 0 {
+				m.Iterations = 0
+			}
+			return &MctsPlayer{name, m}
+		}
+		return &CpuPlayer{name, *cpuLevel, budget, game.NewTable(tableBits)}
+	}
+
+	var p [2]Player
+	if *demoMode {
+		p[0] = newCpuPlayer("CPU 1")
+		p[1] = newCpuPlayer("CPU 2")
+	} else {
+		p[0] = &HumanPlayer{"You"}
+		p[1] = newCpuPlayer("CPU")
+		if askPlaying() == dotsboxes.X {
+			p[0], p[1] = p[1], p[0]
+		}
+	}
+
+	i := 0
+	for {
+		if s.MustPass() {
+			fmt.Println("Another turn for", p[i^1].Name())
+			s = s.Pass()
+			i ^= 1
+			continue
+		}
+		board.PrintLines(os.Stdout, s)
+		if t := p[i].Next(s); t == nil {
+			break
+		} else {
+			s = t
+		}
+		who := p[i].Name()
+		fmt.Println(who, "drew", s.LastMove)
+		i ^= 1
+	}
+
+	o, x := s.Count()
+	fmt.Printf("Game over (%d : %d).  ", o, x)
+	switch s.Eval() {
+	case game.Won:
+		fmt.Println(p[0].Name(), "won")
+	case game.Lost:
+		fmt.Println(p[1].Name(), "won")
+	default:
+		fmt.Println("It was a draw")
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"github.com/z-rui/game/hex"
+	"log"
+	"strings"
+	"unicode"
+)
+
+type HumanPlayer struct {
+	name string
+}
+
+func (p *HumanPlayer) Name() string {
+	return p.name
+}
+
+func askPlaying() hex.Cell {
+	for {
+		fmt.Print("Do you want to play as O or X? ")
+		answer, err := stdin.ReadString('\n')
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if err == nil && len(answer) >= 2 {
+			switch unicode.ToUpper(rune(answer[0])) {
+			case 'O':
+				return hex.O
+			case 'X':
+				return hex.X
+			}
+		}
+		fmt.Println("Sorry, but that does not make sense.")
+	}
+}
+
+func (p *HumanPlayer) Next(s *hex.State) (t *hex.State) {
+	if s.IsEnd() {
+		return nil
+	}
+	for {
+		if s.Swap && s.Plies == 1 {
+			fmt.Print("Where do you want to go (or swap)? ")
+		} else {
+			fmt.Print("Where do you want to go? ")
+		}
+		coord, err := stdin.ReadString('\n')
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if m, err := hex.ParseMove(strings.TrimSpace(coord)); err == nil {
+			t = s.Move(m)
+			if t != nil {
+				return
+			}
+		}
+		fmt.Println("Sorry, but that does not make sense.")
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"github.com/z-rui/game/kalah"
+	"log"
+	"strings"
+	"unicode"
+)
+
+type HumanPlayer struct {
+	name string
+}
+
+func (p *HumanPlayer) Name() string {
+	return p.name
+}
+
+func askPlaying() kalah.Player {
+	for {
+		fmt.Print("Do you want to play as O or X? ")
+		answer, err := stdin.ReadString('\n')
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if err == nil && len(answer) >= 2 {
+			switch unicode.ToUpper(rune(answer[0])) {
+			case 'O':
+				return kalah.O
+			case 'X':
+				return kalah.X
+			}
+		}
+		fmt.Println("Sorry, but that does not make sense.")
+	}
+}
+
+func (p *HumanPlayer) Next(s *kalah.State) (t *kalah.State) {
+	if s.IsEnd() {
+		return nil
+	}
+	for {
+		fmt.Print("Which pit do you want to sow? ")
+		pit, err := stdin.ReadString('\n')
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if m, err := kalah.ParseMove(strings.TrimSpace(pit)); err == nil {
+			t = s.Move(m)
+			if t != nil {
+				return
+			}
+		}
+		fmt.Println("Sorry, but that does not make sense.")
+	}
+}
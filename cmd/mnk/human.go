@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"github.com/z-rui/game/mnk"
+	"log"
+	"strings"
+	"unicode"
+)
+
+type HumanPlayer struct {
+	name string
+}
+
+func (p *HumanPlayer) Name() string {
+	return p.name
+}
+
+func askPlaying() mnk.Cell {
+	for {
+		fmt.Print("Do you want to play as O or X? ")
+		answer, err := stdin.ReadString('\n')
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if err == nil && len(answer) >= 2 {
+			switch unicode.ToUpper(rune(answer[0])) {
+			case 'O':
+				return mnk.O
+			case 'X':
+				return mnk.X
+			}
+		}
+		fmt.Println("Sorry, but that does not make sense.")
+	}
+}
+
+func (p *HumanPlayer) Next(s *mnk.State) (t *mnk.State) {
+	if s.IsEnd() {
+		return nil
+	}
+	for {
+		fmt.Print("Where do you want to go? ")
+		coord, err := stdin.ReadString('\n')
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if m, err := mnk.ParseMove(strings.TrimSpace(coord)); err == nil {
+			t = s.Move(m)
+			if t != nil {
+				return
+			}
+		}
+		fmt.Println("Sorry, but that does not make sense.")
+	}
+}
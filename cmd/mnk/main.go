@@ -0,0 +1,111 @@
+// Command mnk is a console-based program to play m,n,k-games,
+// such as Gomoku (the default) and Tic-Tac-Toe (-rows 3 -cols 3 -k 3 -r 0).
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"github.com/z-rui/game"
+	"github.com/z-rui/game/board"
+	"github.com/z-rui/game/mnk"
+	"log"
+	"os"
+	"runtime/pprof"
+	"time"
+)
+
+var (
+	rows          = flag.Int("rows", mnk.Gomoku.M, "Number of rows")
+	cols          = flag.Int("cols", mnk.Gomoku.N, "Number of columns")
+	inRow         = flag.Int("k", mnk.Gomoku.K, "Number of pieces in a row to win")
+	radius        = flag.Int("r", mnk.Gomoku.Radius, "Cpu only considers cells within this distance from a piece (0: all)")
+	cpuLevel      = flag.Uint("L", 3, "CPU Level: 1(weakest)...9(strongest)")
+	demoMode      = flag.Bool("a", false, "Two Cpus play with each other")
+	cpuTime       = flag.Float64("t", 0, "Seconds per CPU move (overrides -L)")
+	mctsMode      = flag.Bool("m", false, "Cpu uses Monte Carlo tree search")
+	mctsPlayouts  = flag.Int("n", 10000, "Playouts per move for Monte Carlo tree search")
+	verboseSearch = flag.Bool("v", false, "Show Cpu decision details")
+	boxChars      = flag.Bool("U", false, "Use box-drawing characters")
+	cpuProfile    = flag.String("p", "", "Write cpu profile to file")
+)
+
+var (
+	stdin = bufio.NewReader(os.Stdin)
+)
+
+type Player interface {
+	Next(s *mnk.State) *mnk.State
+	Name() string
+}
+
+func main() {
+	flag.Parse()
+	if *cpuLevel < 1 {
+		*cpuLevel = 1
+	}
+	g := mnk.Game{M: *rows, N: *cols, K: *inRow, Radius: *radius}
+	s, err := g.NewState()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if *cpuProfile != "" {
+		f, err := os.Create(*cpuProfile)
+		if err != nil {
+			panic(err)
+		}
+		pprof.StartCPUProfile(f)
+		defer pprof.StopCPUProfile()
+	}
+
+	budget := time.Duration(*cpuTime * float64(time.Second))
+	newCpuPlayer := func(name string) Player {
+		if *mctsMode {
+			m := &game.MCTS{Iterations: *mctsPlayouts, Budget: budget}
+			if budget > 0 {
+				m.Iterations = 0
+			}
+			return &MctsPlayer{name, m}
+		}
+		return &CpuPlayer{name, *cpuLevel, budget, game.NewTable(tableBits)}
+	}
+
+	var p [2]Player
+	if *demoMode {
+		p[0] = newCpuPlayer("CPU 1")
+		p[1] = newCpuPlayer("CPU 2")
+	} else {
+		p[0] = &HumanPlayer{"You"}
+		p[1] = newCpuPlayer("CPU")
+		if askPlaying() == mnk.X {
+			p[0], p[1] = p[1], p[0]
+		}
+	}
+
+	i := 0
+	for {
+		if *boxChars {
+			board.Print(os.Stdout, s, board.UnicodeBox)
+		} else {
+			board.Print(os.Stdout, s, board.AsciiBox)
+		}
+		if t := p[i].Next(s); t == nil {
+			break
+		} else {
+			s = t
+		}
+		who := p[i].Name()
+		fmt.Println(who, "went", s.LastMove)
+		i ^= 1
+	}
+
+	fmt.Print("Game over.  ")
+	switch s.Eval() {
+	case game.Won:
+		fmt.Println(p[0].Name(), "won")
+	case game.Lost:
+		fmt.Println(p[1].Name(), "won")
+	default:
+		fmt.Println("It was a draw")
+	}
+}
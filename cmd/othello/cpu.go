@@ -1,14 +1,31 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/z-rui/game"
 	"github.com/z-rui/game/othello"
+	"github.com/z-rui/game/othello/book"
+	"math/rand"
+	"time"
+)
+
+// tableBits is the size of the transposition table used by CpuPlayer.
+const tableBits = 18
+
+// Parameters of generating an opening book by self-play:
+// the number of plies recorded in each game, and how much worse
+// than the best move a move may be and still be chosen.
+const (
+	bookPlies  = 10
+	bookMargin = 6
 )
 
 type CpuPlayer struct {
-	name  string
-	level uint
+	name   string
+	level  uint
+	budget time.Duration // if non-zero, search by time instead of level
+	table  *game.Table
 }
 
 func (p *CpuPlayer) Name() string {
@@ -16,26 +33,117 @@ func (p *CpuPlayer) Name() string {
 }
 
 func (p *CpuPlayer) Next(s *othello.State) *othello.State {
-	var next game.State
-	findMin := s.Turn == othello.X
-	if *verboseSearch {
-		var eval game.Evaluation
-		for _, t := range s.Next() {
-			ns, e := game.MinMax(t, p.level, !findMin)
-			fmt.Printf("Move %v: value = %v", t.(*othello.State).LastMove, e)
-			if ns != nil {
-				fmt.Printf(", opponent = %v", ns.(*othello.State).LastMove)
-			}
-			fmt.Println()
-			if next == nil || (findMin && e < eval || !findMin && e > eval) {
-				next, eval = t, e
-			}
+	start := time.Now()
+	if s.Empties() <= *endgameEmpties {
+		// the solver has half of the budget, and the search
+		// the time left if the solver runs out of it
+		if t, err := p.solve(s, p.budget/2); err == nil {
+			return t
 		}
+	}
+	opt := game.Options{
+		Table:   p.table,
+		Workers: *cpuWorkers,
+		Killers: true,
+		History: true,
+	}
+	if p.budget == 0 {
+		opt.MaxDepth = p.level
 	} else {
-		next, _ = game.MinMax(s, p.level, findMin)
+		opt.Budget = max(p.budget-time.Since(start), time.Millisecond)
+	}
+	r, _ := othello.Search(context.Background(), s, s.Turn == othello.X, opt)
+	if *verboseSearch {
+		printResult(r)
+	}
+	return r.Next
+}
+
+// solve plays the endgame perfectly, within the budget if non-zero.
+func (p *CpuPlayer) solve(s *othello.State, budget time.Duration) (*othello.State, error) {
+	opt := game.Options{
+		Budget:  budget,
+		Table:   p.table,
+		Workers: *cpuWorkers,
+		PVS:     true,
+		Killers: true,
+		History: true,
+	}
+	diff, line, err := othello.Solve(context.Background(), s, opt)
+	if err != nil {
+		if *verboseSearch {
+			fmt.Println("Endgame not solved:", err)
+		}
+		return nil, err
+	}
+	if s.Turn == othello.X {
+		diff = -diff
+	}
+	if diff > 0 {
+		fmt.Printf("%s sees a forced win by %d discs\n", p.name, diff)
+	}
+	if *verboseSearch {
+		fmt.Printf("Endgame: value = %d, line:", diff)
+		for _, t := range line {
+			fmt.Print(" ", t.LastMove)
+		}
+		fmt.Println()
+	}
+	if len(line) == 0 {
+		return nil, nil
 	}
-	if next == nil {
-		return nil
+	return line[0], nil
+}
+
+type MctsPlayer struct {
+	name   string
+	engine *game.MCTS
+}
+
+func (p *MctsPlayer) Name() string {
+	return p.name
+}
+
+func (p *MctsPlayer) Next(s *othello.State) *othello.State {
+	res, _ := p.engine.Search(context.Background(), s, s.Turn == othello.X)
+	r := game.ResultOf[*othello.State](res)
+	if *verboseSearch {
+		printResult(r)
+	}
+	return r.Next
+}
+
+// printResult prints the details of a search.
+func printResult(r *game.TypedResult[*othello.State]) {
+	fmt.Printf("Depth %d: value = %v, PV:", r.Depth, r.Eval)
+	for _, t := range r.PV {
+		fmt.Print(" ", t.LastMove)
+	}
+	fmt.Println()
+	fmt.Printf("%d nodes, %d cutoffs in %v (%.0f nodes/s)\n",
+		r.Nodes, r.Cutoffs, r.Elapsed, r.NPS())
+}
+
+// BookPlayer plays the moves in the opening book,
+// and leaves the others to Player.
+type BookPlayer struct {
+	Player
+	book *book.Book
+	rand *rand.Rand
+}
+
+// withBook returns p playing with the book, or p itself if b is nil.
+func withBook(p Player, b *book.Book) Player {
+	if b == nil {
+		return p
+	}
+	return &BookPlayer{p, b, rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (p *BookPlayer) Next(s *othello.State) *othello.State {
+	if t := p.book.Pick(s, p.rand); t != nil {
+		fmt.Println(p.Name(), "plays a book move")
+		return t
 	}
-	return next.(*othello.State)
+	return p.Player.Next(s)
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/z-rui/game/othello"
 	"log"
+	"strings"
 	"unicode"
 )
 
@@ -47,10 +48,7 @@ func (p *HumanPlayer) Next(s *othello.State) (t *othello.State) {
 		if err != nil {
 			log.Fatalln(err)
 		}
-		if len(coord) >= 3 {
-			var m othello.Move
-			m.I = uint8(unicode.ToUpper(rune(coord[0])) - 'A')
-			m.J = uint8(coord[1] - '1')
+		if m, err := othello.ParseMove(strings.TrimSpace(coord)); err == nil {
 			t = s.Move(m)
 			if t != nil {
 				return
@@ -5,18 +5,32 @@ import (
 	"bufio"
 	"flag"
 	"fmt"
+	"github.com/z-rui/game"
 	"github.com/z-rui/game/board"
 	"github.com/z-rui/game/othello"
+	"github.com/z-rui/game/othello/book"
+	"log"
+	"math/rand"
 	"os"
 	"runtime/pprof"
+	"time"
 )
 
 var (
-	cpuLevel      = flag.Uint("L", 5, "CPU Level: 1(weakest)...9(strongest)")
-	demoMode      = flag.Bool("a", false, "Two Cpus play with each other")
-	verboseSearch = flag.Bool("v", false, "Show Cpu decision details")
-	boxChars      = flag.Bool("U", false, "Use box-drawing characters")
-	cpuProfile    = flag.String("p", "", "Write cpu profile to file")
+	cpuLevel       = flag.Uint("L", 5, "CPU Level: 1(weakest)...9(strongest)")
+	demoMode       = flag.Bool("a", false, "Two Cpus play with each other")
+	cpuTime        = flag.Float64("t", 0, "Seconds per CPU move (overrides -L)")
+	cpuWorkers     = flag.Int("j", 1, "Number of CPU search workers")
+	endgameEmpties = flag.Int("e", 12, "Solve the endgame when this many empty cells remain")
+	mctsMode       = flag.Bool("m", false, "Cpu uses Monte Carlo tree search")
+	mctsPlayouts   = flag.Int("n", 10000, "Playouts per move for Monte Carlo tree search")
+	verboseSearch  = flag.Bool("v", false, "Show Cpu decision details")
+	boxChars       = flag.Bool("U", false, "Use box-drawing characters")
+	cpuProfile     = flag.String("p", "", "Write cpu profile to file")
+	bookFile       = flag.String("b", "", "Opening book file (default: the built-in book)")
+	noBook         = flag.Bool("B", false, "Cpu does not use the opening book")
+	generateBook   = flag.Int("g", 0, "Write a book generated from this many self-play games and exit")
+	boardSize      = flag.Int("size", othello.N, "Board size: an even number from 4 to 16")
 )
 
 var (
@@ -42,19 +56,49 @@ func main() {
 		defer pprof.StopCPUProfile()
 	}
 
+	if *boardSize%2 != 0 || *boardSize < othello.MinN || *boardSize > othello.MaxN {
+		log.Fatalf("bad board size %d: must be even and between %d and %d",
+			*boardSize, othello.MinN, othello.MaxN)
+	}
+	if *generateBook > 0 {
+		r := rand.New(rand.NewSource(time.Now().UnixNano()))
+		b := book.Generate(*generateBook, bookPlies, *cpuLevel, bookMargin, r)
+		if err := b.Write(os.Stdout); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+	var openings *book.Book
+	if !*noBook && *boardSize == othello.N {
+		// the book is for the standard board
+		openings = loadBook(*bookFile)
+	}
+
+	budget := time.Duration(*cpuTime * float64(time.Second))
+	newCpuPlayer := func(name string) Player {
+		if *mctsMode {
+			m := &game.MCTS{Iterations: *mctsPlayouts, Budget: budget}
+			if budget > 0 {
+				m.Iterations = 0
+			}
+			return withBook(&MctsPlayer{name, m}, openings)
+		}
+		return withBook(&CpuPlayer{name, *cpuLevel, budget, game.NewTable(tableBits)}, openings)
+	}
+
 	var p [2]Player
 	if *demoMode {
-		p[0] = &CpuPlayer{"CPU 1", *cpuLevel}
-		p[1] = &CpuPlayer{"CPU 2", *cpuLevel}
+		p[0] = newCpuPlayer("CPU 1")
+		p[1] = newCpuPlayer("CPU 2")
 	} else {
 		p[0] = &HumanPlayer{"You"}
-		p[1] = &CpuPlayer{"CPU", *cpuLevel}
+		p[1] = newCpuPlayer("CPU")
 		if askPlaying() == othello.X {
 			p[0], p[1] = p[1], p[0]
 		}
 	}
 
-	s := othello.NewState()
+	s := othello.NewStateSize(*boardSize)
 	i := 0
 	for {
 		if *boxChars {
@@ -89,3 +133,21 @@ func main() {
 		fmt.Println("It was a draw")
 	}
 }
+
+// loadBook loads the opening book from the file,
+// or the built-in book if name is empty.
+func loadBook(name string) *book.Book {
+	if name == "" {
+		return book.Default()
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer f.Close()
+	b, err := book.Load(f)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return b
+}
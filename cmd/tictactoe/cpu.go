@@ -1,14 +1,25 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/z-rui/game"
 	"github.com/z-rui/game/tictactoe"
+	"math/rand"
+	"time"
 )
 
+// tableBits is the size of the transposition table used by CpuPlayer.
+const tableBits = 12
+
+// perfectLevel is the CPU level played by PerfectPlayer.
+const perfectLevel = 10
+
 type CpuPlayer struct {
-	name  string
-	level uint
+	name   string
+	level  uint
+	budget time.Duration // if non-zero, search by time instead of level
+	table  *game.Table
 }
 
 func (p *CpuPlayer) Name() string {
@@ -16,26 +27,69 @@ func (p *CpuPlayer) Name() string {
 }
 
 func (p *CpuPlayer) Next(s *tictactoe.State) *tictactoe.State {
-	var next game.State
-	findMin := s.Turn == tictactoe.X
+	opt := game.Options{
+		Budget:  p.budget,
+		Table:   p.table,
+		Killers: true,
+		History: true,
+	}
+	if p.budget == 0 {
+		opt.MaxDepth = p.level
+	}
+	r, _ := game.SearchOf(context.Background(), s, s.Turn == tictactoe.X, opt)
 	if *verboseSearch {
-		var eval game.Evaluation
-		for _, t := range s.Next() {
-			ns, e := game.MinMax(t, p.level, !findMin)
-			fmt.Printf("Move %v: value = %v", t.(*tictactoe.State).LastMove, e)
-			if ns != nil {
-				fmt.Printf(", opponent = %v", ns.(*tictactoe.State).LastMove)
-			}
-			fmt.Println()
-			if next == nil || (findMin && e < eval || !findMin && e > eval) {
-				next, eval = t, e
-			}
-		}
-	} else {
-		next, _ = game.MinMax(s, p.level, findMin)
+		printResult(r)
 	}
-	if next == nil {
+	return r.Next
+}
+
+// PerfectPlayer plays one of the best moves at random,
+// as told by the solved database.
+type PerfectPlayer struct {
+	name string
+	rand *rand.Rand
+}
+
+func (p *PerfectPlayer) Name() string {
+	return p.name
+}
+
+func (p *PerfectPlayer) Next(s *tictactoe.State) *tictactoe.State {
+	moves := tictactoe.BestMoves(s)
+	if len(moves) == 0 {
 		return nil
 	}
-	return next.(*tictactoe.State)
+	if *verboseSearch {
+		fmt.Printf("Value = %v, best moves: %v\n", tictactoe.Value(s), moves)
+	}
+	return s.Move(moves[p.rand.Intn(len(moves))])
+}
+
+type MctsPlayer struct {
+	name   string
+	engine *game.MCTS
+}
+
+func (p *MctsPlayer) Name() string {
+	return p.name
+}
+
+func (p *MctsPlayer) Next(s *tictactoe.State) *tictactoe.State {
+	res, _ := p.engine.Search(context.Background(), s, s.Turn == tictactoe.X)
+	r := game.ResultOf[*tictactoe.State](res)
+	if *verboseSearch {
+		printResult(r)
+	}
+	return r.Next
+}
+
+// printResult prints the details of a search.
+func printResult(r *game.TypedResult[*tictactoe.State]) {
+	fmt.Printf("Depth %d: value = %v, PV:", r.Depth, r.Eval)
+	for _, t := range r.PV {
+		fmt.Print(" ", t.LastMove)
+	}
+	fmt.Println()
+	fmt.Printf("%d nodes, %d cutoffs in %v (%.0f nodes/s)\n",
+		r.Nodes, r.Cutoffs, r.Elapsed, r.NPS())
 }
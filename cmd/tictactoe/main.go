@@ -8,13 +8,18 @@ import (
 	"github.com/z-rui/game"
 	"github.com/z-rui/game/board"
 	"github.com/z-rui/game/tictactoe"
+	"math/rand"
 	"os"
 	"runtime/pprof"
+	"time"
 )
 
 var (
-	cpuLevel      = flag.Uint("L", 9, "CPU Level: 1(weakest)...9(strongest)")
+	cpuLevel      = flag.Uint("L", 9, "CPU Level: 1(weakest)...9(strongest), 10(perfect, instant)")
 	demoMode      = flag.Bool("a", false, "Two Cpus play with each other")
+	cpuTime       = flag.Float64("t", 0, "Seconds per CPU move (overrides -L)")
+	mctsMode      = flag.Bool("m", false, "Cpu uses Monte Carlo tree search")
+	mctsPlayouts  = flag.Int("n", 10000, "Playouts per move for Monte Carlo tree search")
 	verboseSearch = flag.Bool("v", false, "Show Cpu decision details")
 	boxChars      = flag.Bool("U", false, "Use box-drawing characters")
 	cpuProfile    = flag.String("p", "", "Write cpu profile to file")
@@ -43,13 +48,28 @@ func main() {
 		defer pprof.StopCPUProfile()
 	}
 
+	budget := time.Duration(*cpuTime * float64(time.Second))
+	newCpuPlayer := func(name string) Player {
+		if *mctsMode {
+			m := &game.MCTS{Iterations: *mctsPlayouts, Budget: budget}
+			if budget > 0 {
+				m.Iterations = 0
+			}
+			return &MctsPlayer{name, m}
+		}
+		if *cpuLevel >= perfectLevel {
+			return &PerfectPlayer{name, rand.New(rand.NewSource(time.Now().UnixNano()))}
+		}
+		return &CpuPlayer{name, *cpuLevel, budget, game.NewTable(tableBits)}
+	}
+
 	var p [2]Player
 	if *demoMode {
-		p[0] = &CpuPlayer{"CPU 1", *cpuLevel}
-		p[1] = &CpuPlayer{"CPU 2", *cpuLevel}
+		p[0] = newCpuPlayer("CPU 1")
+		p[1] = newCpuPlayer("CPU 2")
 	} else {
 		p[0] = &HumanPlayer{"You"}
-		p[1] = &CpuPlayer{"CPU", *cpuLevel}
+		p[1] = newCpuPlayer("CPU")
 		if askPlaying() == tictactoe.X {
 			p[0], p[1] = p[1], p[0]
 		}
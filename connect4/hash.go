@@ -0,0 +1,39 @@
+package connect4
+
+import "math/rand"
+
+// Zobrist keys for hashing a state.
+var (
+	zobristCell [Rows][Cols][2]uint64 // for O and X at each cell
+	zobristX    uint64                // for X's turn
+)
+
+// Zobrist keys generation
+func init() {
+	r := rand.New(rand.NewSource(Rows * Cols))
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			zobristCell[i][j][0] = r.Uint64()
+			zobristCell[i][j][1] = r.Uint64()
+		}
+	}
+	zobristX = r.Uint64()
+}
+
+// Hash returns the Zobrist hash of the state.
+func (s *State) Hash() (h uint64) {
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			switch s.Board[i][j] {
+			case O:
+				h ^= zobristCell[i][j][0]
+			case X:
+				h ^= zobristCell[i][j][1]
+			}
+		}
+	}
+	if s.Turn == X {
+		h ^= zobristX
+	}
+	return
+}
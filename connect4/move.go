@@ -0,0 +1,41 @@
+package connect4
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Move represents a cell on the board
+type Move struct {
+	I, J uint8
+}
+
+var invalidMove = Move{Rows, Cols}
+
+// String converts a Move to the string representation,
+// which is the number of the column.
+func (m Move) String() string {
+	return strconv.Itoa(int(m.J) + 1)
+}
+
+// ParseMove converts the string representation of a move,
+// the number of the column, back to a Move.  The row is left
+// to State.Move, which finds where the disc falls.
+func ParseMove(str string) (Move, error) {
+	j, err := strconv.Atoi(str)
+	if err != nil || j < 1 || j > Cols {
+		return invalidMove, errors.New("connect4: bad move " + str)
+	}
+	return Move{0, uint8(j - 1)}, nil
+}
+
+// Valid tells if the move is a valid position (not out-of-bound)
+// on the board.
+func (m Move) Valid() bool {
+	return m.I < Rows && m.J < Cols
+}
+
+// MoveKey returns the column of the last move.
+func (s *State) MoveKey() int {
+	return int(s.LastMove.J)
+}
@@ -0,0 +1,42 @@
+package connect4
+
+import "github.com/z-rui/game"
+
+// Moves appends the moves allowed in the current state to moves,
+// in the same order as Next.  A move is the column to drop a disc.
+func (s *State) Moves(moves []int) []int {
+	if s.IsEnd() {
+		return moves
+	}
+	for _, j := range columnOrder {
+		if s.Board[0][j] == Empty {
+			moves = append(moves, j)
+		}
+	}
+	return moves
+}
+
+// Play makes the move in place.
+func (s *State) Play(move int) {
+	s.history = append(s.history, s.LastMove)
+	i := s.top(move)
+	s.Board[i][move] = s.Turn
+	s.LastMove = Move{uint8(i), uint8(move)}
+	s.Turn ^= O ^ X
+}
+
+// Undo takes back the last move played.
+func (s *State) Undo() {
+	s.Board[s.LastMove.I][s.LastMove.J] = Empty
+	n := len(s.history) - 1
+	s.LastMove = s.history[n]
+	s.history = s.history[:n]
+	s.Turn ^= O ^ X
+}
+
+// Clone returns a copy of the current state.
+func (s *State) Clone() game.Mover {
+	t := *s
+	t.history = nil
+	return &t
+}
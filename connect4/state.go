@@ -0,0 +1,252 @@
+// Package connect4 implements the Connect Four game: two players take
+// turns to drop their discs into a column of an upright board,
+// where a disc falls to the lowest empty cell.  The first to get
+// four in a row, horizontally, vertically or diagonally, wins.
+package connect4
+
+import "github.com/z-rui/game"
+
+// Rows and Cols are the dimension of the board.
+const (
+	Rows = 6
+	Cols = 7
+)
+
+// K is the number of discs in a row to win.
+const K = 4
+
+// Cell represents a cell of the board.
+// It has three states: Empty, O and X.
+type Cell uint8
+
+const (
+	Empty Cell = iota
+	O
+	X
+)
+
+// String converts a cell to the string representation.
+func (c Cell) String() string {
+	switch c {
+	case O:
+		return "O"
+	case X:
+		return "X"
+	default:
+		return " "
+	}
+}
+
+// State represents the current state of the game.
+// The row 0 is the top of the board.
+type State struct {
+	Board    [Rows][Cols]Cell
+	LastMove Move   // the cell where the last disc fell
+	Turn     Cell   // must be O or X
+	history  []Move // last moves before those played by Play
+}
+
+// NewState returns a new state at the start of the game.
+func NewState() *State {
+	s := new(State)
+	s.LastMove = invalidMove
+	s.Turn = O
+	return s
+}
+
+// Dim returns the dimension of the board
+func (s *State) Dim() (int, int) {
+	return Rows, Cols
+}
+
+// Get returns the string representation at (i, j)
+func (s *State) Get(i, j int) string {
+	return s.Board[i][j].String()
+}
+
+// cell returns the cell at (i, j), or Empty if it is off the board.
+func (s *State) cell(i, j int) Cell {
+	if i < 0 || i >= Rows || j < 0 || j >= Cols {
+		return Empty
+	}
+	return s.Board[i][j]
+}
+
+// directions are the four directions of lines.
+var directions = [4][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}}
+
+// Won tells if the last move made four in a row.
+func (s *State) Won() bool {
+	if !s.LastMove.Valid() {
+		return false
+	}
+	i, j := int(s.LastMove.I), int(s.LastMove.J)
+	c := s.Board[i][j]
+	for _, d := range directions {
+		n := 1
+		for k := 1; s.cell(i+k*d[0], j+k*d[1]) == c; k++ {
+			n++
+		}
+		for k := 1; s.cell(i-k*d[0], j-k*d[1]) == c; k++ {
+			n++
+		}
+		if n >= K {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEnd tells if the game has ended.
+func (s *State) IsEnd() bool {
+	if s.Won() {
+		return true
+	}
+	for j := 0; j < Cols; j++ {
+		if s.Board[0][j] == Empty {
+			return false
+		}
+	}
+	return true
+}
+
+// Weights of the evaluation.
+const (
+	centerWeight = 3  // of a disc in the center column
+	threatWeight = 16 // of a cell that would make four in a row
+	parityWeight = 16 // extra for a threat on a row good for the player
+)
+
+// lineWeights are the weights of a line of four cells holding
+// the given number of discs of a player and no disc of the other.
+var lineWeights = [K + 1]game.Evaluation{0, 1, 4, 0, 0}
+
+// Eval returns the evaluation of the current state.
+//
+// A state not ended is evaluated by the lines of four cells
+// that either player can still complete, and the threats:
+// the empty cells that would complete a line.  As the board fills
+// from the bottom, O (the first player) tends to get the threats on
+// the odd rows counted from the bottom, and X those on the even rows;
+// such threats weigh more.
+func (s *State) Eval() (eval game.Evaluation) {
+	if s.Won() {
+		if s.Turn == O {
+			return game.Lost
+		}
+		return game.Won
+	}
+	var threats [Rows][Cols]Cell // O, X, or both (O|X)
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			for _, d := range directions {
+				eval += s.line(i, j, d[0], d[1], &threats)
+			}
+		}
+		switch s.Board[i][Cols/2] {
+		case O:
+			eval += centerWeight
+		case X:
+			eval -= centerWeight
+		}
+	}
+	for i := 0; i < Rows; i++ {
+		odd := (Rows-i)%2 == 1
+		for j := 0; j < Cols; j++ {
+			t := threats[i][j]
+			if t&O != 0 {
+				eval += threatWeight
+				if odd {
+					eval += parityWeight
+				}
+			}
+			if t&X != 0 {
+				eval -= threatWeight
+				if !odd {
+					eval -= parityWeight
+				}
+			}
+		}
+	}
+	return
+}
+
+// line evaluates the line of four cells starting from (i, j)
+// in the direction (di, dj), and marks the cell of a threat.
+func (s *State) line(i, j, di, dj int, threats *[Rows][Cols]Cell) game.Evaluation {
+	ei, ej := i+(K-1)*di, j+(K-1)*dj
+	if ei < 0 || ei >= Rows || ej < 0 || ej >= Cols {
+		return 0
+	}
+	var o, x, ti, tj int
+	for k := 0; k < K; k++ {
+		switch s.Board[i+k*di][j+k*dj] {
+		case O:
+			o++
+		case X:
+			x++
+		default:
+			ti, tj = i+k*di, j+k*dj
+		}
+	}
+	switch {
+	case o == K-1 && x == 0:
+		threats[ti][tj] |= O
+	case x == K-1 && o == 0:
+		threats[ti][tj] |= X
+	case x == 0:
+		return lineWeights[o]
+	case o == 0:
+		return -lineWeights[x]
+	}
+	return 0
+}
+
+// columnOrder is the order in which the columns are tried,
+// from the center outwards.
+var columnOrder = [Cols]int{3, 2, 4, 1, 5, 0, 6}
+
+// Next returns all possible next states.
+func (s *State) Next() (nxt []game.State) {
+	if s.IsEnd() {
+		return
+	}
+	nxt = make([]game.State, 0, Cols)
+	for _, j := range columnOrder {
+		if t := s.Move(Move{0, uint8(j)}); t != nil {
+			nxt = append(nxt, t)
+		}
+	}
+	return
+}
+
+// Move returns the next state after a disc is dropped into
+// the column m.J; m.I is ignored, as the disc falls to the lowest
+// empty row.  It returns nil if the move is not allowed.
+func (s *State) Move(m Move) *State {
+	j := int(m.J)
+	if j >= Cols || s.IsEnd() {
+		return nil
+	}
+	i := s.top(j)
+	if i < 0 {
+		return nil
+	}
+	t := new(State)
+	t.Board = s.Board
+	t.Board[i][j] = s.Turn
+	t.LastMove = Move{uint8(i), uint8(j)}
+	t.Turn = s.Turn ^ (O ^ X)
+	return t
+}
+
+// top returns the row where a disc dropped into the column j
+// falls, or -1 if the column is full.
+func (s *State) top(j int) int {
+	for i := Rows - 1; i >= 0; i-- {
+		if s.Board[i][j] == Empty {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,141 @@
+package connect4
+
+import (
+	"context"
+	"github.com/z-rui/game"
+	"testing"
+)
+
+// play drops discs into the columns, numbered from 1, from the start.
+func play(t *testing.T, cols ...int) *State {
+	s := NewState()
+	for _, j := range cols {
+		if s = s.Move(Move{0, uint8(j - 1)}); s == nil {
+			t.Fatalf("%v: column %d not allowed", cols, j)
+		}
+	}
+	return s
+}
+
+func TestWon(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		cols []int
+	}{
+		{"horizontal", []int{1, 1, 2, 2, 3, 3, 4}},
+		{"vertical", []int{1, 2, 1, 2, 1, 2, 1}},
+		{"diagonal", []int{1, 2, 2, 3, 3, 4, 3, 4, 4, 7, 4}},
+		{"anti-diagonal", []int{7, 6, 6, 5, 5, 4, 5, 4, 4, 1, 4}},
+		{"middle", []int{1, 1, 2, 2, 4, 4, 3}},
+	} {
+		s := play(t, c.cols...)
+		if !s.Won() || !s.IsEnd() || s.Eval() != game.Won {
+			t.Errorf("%s: O has not won", c.name)
+		}
+		if len(s.Next()) != 0 || len(s.Moves(nil)) != 0 {
+			t.Errorf("%s: game goes on after a win", c.name)
+		}
+		s = play(t, c.cols[:len(c.cols)-1]...)
+		if s.Won() || s.IsEnd() {
+			t.Errorf("%s: won with three", c.name)
+		}
+	}
+
+	// X wins
+	s := play(t, 1, 2, 1, 2, 1, 2, 7, 2)
+	if !s.Won() || s.Eval() != game.Lost {
+		t.Errorf("X has not won")
+	}
+}
+
+func TestFull(t *testing.T) {
+	// rows of OXXOOXX and XOOXXOO alternate, with no four in a row
+	s := NewState()
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			s.Board[i][j] = [2]Cell{O, X}[(j+1)/2%2^i%2]
+		}
+	}
+	s.LastMove = Move{0, 0}
+	if s.Won() || !s.IsEnd() || s.Eval() != 0 {
+		t.Errorf("full board not a draw")
+	}
+	if s.Move(Move{0, 0}) != nil || len(s.Next()) != 0 || len(s.Moves(nil)) != 0 {
+		t.Errorf("move allowed on full board")
+	}
+}
+
+func TestPerft(t *testing.T) {
+	for depth, want := range []uint64{
+		1, 7, 49, 343, 2401, 16807, 117649, 823536, 5673234,
+	} {
+		if testing.Short() && depth > 7 {
+			break
+		}
+		if got := game.Perft(NewState(), uint(depth)); got != want {
+			t.Errorf("depth %d: got %d, want %d", depth, got, want)
+		}
+		if depth > 6 {
+			continue
+		}
+		if got := game.Perft(struct{ game.State }{NewState()}, uint(depth)); got != want {
+			t.Errorf("depth %d without mover: got %d, want %d", depth, got, want)
+		}
+	}
+}
+
+func TestEval(t *testing.T) {
+	if e := play(t, 4).Eval(); e <= 0 {
+		t.Errorf("center opening: got %v, want positive", e)
+	}
+	if e := play(t, 1).Eval(); e >= play(t, 4).Eval() {
+		t.Errorf("edge opening not worse than the center: %v", e)
+	}
+	// O threatens at the bottom row, which is odd
+	threat := play(t, 1, 7, 2, 7, 3)
+	if e := threat.Eval(); e < threatWeight+parityWeight {
+		t.Errorf("odd threat of O: got %v, want at least %v", e, threatWeight+parityWeight)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	opt := game.Options{MaxDepth: 6, Table: game.NewTable(16), PVS: true, Killers: true, History: true}
+	// O wins at once in column 4
+	s := play(t, 1, 1, 2, 2, 3, 3)
+	r, _ := game.SearchOf(context.Background(), s, false, opt)
+	if r.Eval != game.WinIn(1) || r.Next.LastMove.J != 3 {
+		t.Errorf("win not found: %v %v", r.Next.LastMove, r.Eval)
+	}
+	// X must block in column 4
+	s = play(t, 1, 1, 2, 2, 3)
+	r, _ = game.SearchOf(context.Background(), s, true, opt)
+	if r.Next.LastMove.J != 3 {
+		t.Errorf("threat not blocked: %v", r.Next.LastMove)
+	}
+	if s1, s2 := play(t, 1, 2, 3), play(t, 3, 2, 1); s1.Hash() != s2.Hash() {
+		t.Errorf("transposed states hashed differently")
+	}
+}
+
+func TestMove(t *testing.T) {
+	for _, str := range []string{"0", "8", "x", ""} {
+		if _, err := ParseMove(str); err == nil {
+			t.Errorf("%q parsed", str)
+		}
+	}
+	m, err := ParseMove("4")
+	if err != nil || m.J != 3 {
+		t.Fatalf("4 parsed as %v, %v", m, err)
+	}
+	// the disc falls to the lowest empty row, whatever m.I is
+	s := NewState().Move(m).Move(Move{0, 3})
+	if s == nil {
+		t.Fatalf("column 4 not allowed")
+	}
+	if s.LastMove != (Move{Rows - 2, 3}) || s.LastMove.String() != "4" {
+		t.Errorf("second disc in column 4 at %v", s.LastMove)
+	}
+	if NewState().Move(Move{0, Cols}) != nil {
+		t.Errorf("move allowed outside the board")
+	}
+}
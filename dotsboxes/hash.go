@@ -0,0 +1,46 @@
+package dotsboxes
+
+import "math/rand"
+
+// Zobrist keys for hashing a state.
+var (
+	zobristLines [2 * MaxSize * (MaxSize + 1)]uint64 // for each line drawn
+	zobristBoxes [MaxSize * MaxSize][2]uint64        // for O and X owning each box
+	zobristX     uint64                              // for X's turn
+	zobristPass  uint64                              // for a player who must pass
+)
+
+// Zobrist keys generation
+func init() {
+	r := rand.New(rand.NewSource(MaxSize))
+	for k := range zobristLines {
+		zobristLines[k] = r.Uint64()
+	}
+	for b := range zobristBoxes {
+		zobristBoxes[b][0] = r.Uint64()
+		zobristBoxes[b][1] = r.Uint64()
+	}
+	zobristX = r.Uint64()
+	zobristPass = r.Uint64()
+}
+
+// Hash returns the Zobrist hash of the state.
+func (s *State) Hash() (h uint64) {
+	for k, drawn := range s.Lines {
+		if drawn {
+			h ^= zobristLines[k]
+		}
+	}
+	for b, c := range s.Boxes {
+		if c != Empty {
+			h ^= zobristBoxes[b][c-O]
+		}
+	}
+	if s.Turn == X {
+		h ^= zobristX
+	}
+	if s.passing {
+		h ^= zobristPass
+	}
+	return
+}
@@ -0,0 +1,97 @@
+package dotsboxes
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Move represents the line from dot (I, J) to the dot on the right,
+// or the dot below if Vertical.
+type Move struct {
+	I, J     int
+	Vertical bool
+}
+
+// Pass is the move of a player who must pass.
+var Pass = Move{-1, -1, false}
+
+var invalidMove = Move{-2, -2, false}
+
+// dot converts a dot to the string representation,
+// such as "C4" for the row C and the column 4.
+func dot(i, j int) string {
+	return string(rune('A'+i)) + strconv.Itoa(j+1)
+}
+
+// String converts a Move to the string representation by the dots
+// at the ends of the line, such as "A1-A2" or "A1-B1", or "pass".
+func (m Move) String() string {
+	switch {
+	case m == Pass:
+		return "pass"
+	case m.I < 0 || m.J < 0:
+		return "(none)"
+	case m.Vertical:
+		return dot(m.I, m.J) + "-" + dot(m.I+1, m.J)
+	}
+	return dot(m.I, m.J) + "-" + dot(m.I, m.J+1)
+}
+
+// parseDot converts the string representation of a dot back.
+func parseDot(str string) (i, j int, ok bool) {
+	if len(str) < 2 {
+		return
+	}
+	c := str[0]
+	if 'a' <= c && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	n, err := strconv.Atoi(str[1:])
+	if c < 'A' || c > 'A'+MaxSize || err != nil || n < 1 || n > MaxSize+1 {
+		return
+	}
+	return int(c - 'A'), n - 1, true
+}
+
+// ParseMove converts the string representation of a move back
+// to a Move.  The dots may be in either order.  It does not tell
+// if the move is on a particular grid.
+func ParseMove(str string) (Move, error) {
+	if strings.EqualFold(str, "pass") {
+		return Pass, nil
+	}
+	bad := errors.New("dotsboxes: bad move " + str)
+	a, b, found := strings.Cut(str, "-")
+	if !found {
+		return invalidMove, bad
+	}
+	i1, j1, ok1 := parseDot(a)
+	i2, j2, ok2 := parseDot(b)
+	if !ok1 || !ok2 {
+		return invalidMove, bad
+	}
+	if i1 > i2 || j1 > j2 {
+		i1, j1, i2, j2 = i2, j2, i1, j1
+	}
+	switch {
+	case i1 == i2 && j2 == j1+1:
+		return Move{i1, j1, false}, nil
+	case j1 == j2 && i2 == i1+1:
+		return Move{i1, j1, true}, nil
+	}
+	return invalidMove, bad
+}
+
+// MoveKey returns the index of the line of the last move,
+// or the number of lines for a pass.  It is 0 at the start,
+// where there is no last move.
+func (s *State) MoveKey() int {
+	switch s.LastMove {
+	case Pass:
+		return len(s.Lines)
+	case invalidMove:
+		return 0
+	}
+	return s.line(s.LastMove)
+}
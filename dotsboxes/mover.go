@@ -0,0 +1,94 @@
+package dotsboxes
+
+import "github.com/z-rui/game"
+
+// undo is what Undo needs to take back a move.
+type undo struct {
+	lastMove Move
+	passing  bool
+}
+
+// Moves appends the moves allowed in the current state to moves,
+// in the same order as Next.  A move is the index of the line,
+// or the number of lines for a pass.  The lines completing a box
+// come first, then those leaving no box with three sides, and
+// then the rest.
+func (s *State) Moves(moves []int) []int {
+	switch {
+	case s.IsEnd():
+		return moves
+	case s.passing:
+		return append(moves, len(s.Lines))
+	}
+	for kind := 0; kind < 3; kind++ {
+		for k, drawn := range s.Lines {
+			if !drawn && s.kind(k) == kind {
+				moves = append(moves, k)
+			}
+		}
+	}
+	return moves
+}
+
+// kind tells if line k completes a box (0), leaves no box with
+// three sides (1), or gives a box away (2).
+func (s *State) kind(k int) int {
+	kind := 1
+	for _, b := range s.boxes[k] {
+		if b < 0 {
+			continue
+		}
+		switch s.drawn(b) {
+		case 3:
+			return 0
+		case 2:
+			kind = 2
+		}
+	}
+	return kind
+}
+
+// Play makes the move in place.
+func (s *State) Play(move int) {
+	s.history = append(s.history, undo{s.LastMove, s.passing})
+	if move == len(s.Lines) {
+		s.LastMove = Pass
+		s.passing = false
+	} else {
+		s.Lines[move] = true
+		completed := false
+		for _, b := range s.boxes[move] {
+			if b >= 0 && s.drawn(b) == 4 {
+				s.Boxes[b] = s.Turn
+				completed = true
+			}
+		}
+		s.LastMove = s.move(move)
+		s.passing = completed && !s.IsEnd()
+	}
+	s.Turn ^= O ^ X
+}
+
+// Undo takes back the last move played.
+func (s *State) Undo() {
+	if s.LastMove != Pass {
+		k := s.line(s.LastMove)
+		s.Lines[k] = false
+		for _, b := range s.boxes[k] {
+			if b >= 0 {
+				s.Boxes[b] = Empty
+			}
+		}
+	}
+	n := len(s.history) - 1
+	u := s.history[n]
+	s.LastMove = u.lastMove
+	s.passing = u.passing
+	s.history = s.history[:n]
+	s.Turn ^= O ^ X
+}
+
+// Clone returns a copy of the current state.
+func (s *State) Clone() game.Mover {
+	return s.clone()
+}
@@ -0,0 +1,336 @@
+// Package dotsboxes implements Dots and Boxes: two players take turns
+// to draw a line between two dots next to each other on a grid.
+// A player who draws the fourth side of a box owns the box, and must
+// draw another line, unless all the boxes are owned.  The player who
+// owns more boxes wins; the game ends as soon as a player owns more
+// than half of them.
+//
+// A State does not let one player draw twice in a row.  Once a box is
+// completed, the other player must pass (see MustPass), which gives
+// the turn back to its owner; so the search in package game sees
+// the players alternate, one line or one pass at a time.
+package dotsboxes
+
+import (
+	"errors"
+	"github.com/z-rui/game"
+)
+
+// Cell represents the owner of a box.
+// It has three states: Empty, O and X.
+type Cell uint8
+
+const (
+	Empty Cell = iota
+	O
+	X
+)
+
+// String converts a cell to the string representation.
+func (c Cell) String() string {
+	switch c {
+	case O:
+		return "O"
+	case X:
+		return "X"
+	default:
+		return " "
+	}
+}
+
+// MaxSize is the largest number of rows or columns of boxes.
+const MaxSize = 8
+
+// Game describes a game of Dots and Boxes.
+type Game struct {
+	Rows, Cols int // of boxes, each 1 less than that of dots
+}
+
+// Standard is the usual game on a 3×3 grid of boxes (4×4 dots).
+var Standard = Game{Rows: 3, Cols: 3}
+
+// rules is what the states of a game share.
+type rules struct {
+	Game
+	boxes [][2]int // of each line: the boxes on either side, or -1
+}
+
+// State represents the current state of the game.
+type State struct {
+	*rules
+	// Lines tells the lines drawn: the horizontal lines in row-major
+	// order, followed by the vertical lines in row-major order.
+	Lines    []bool
+	Boxes    []Cell // owners of the boxes in row-major order
+	LastMove Move
+	Turn     Cell   // must be O or X
+	passing  bool   // the player to move must pass
+	history  []undo // to take back the moves played by Play
+}
+
+// NewState returns a new state at the start of the game.
+func (g Game) NewState() (*State, error) {
+	if g.Rows < 1 || g.Cols < 1 || g.Rows > MaxSize || g.Cols > MaxSize {
+		return nil, errors.New("dotsboxes: bad grid size")
+	}
+	r := newRules(g)
+	s := &State{
+		rules:    r,
+		Lines:    make([]bool, len(r.boxes)),
+		Boxes:    make([]Cell, g.Rows*g.Cols),
+		LastMove: invalidMove,
+		Turn:     O,
+	}
+	return s, nil
+}
+
+// newRules prepares the rules of the game.
+func newRules(g Game) *rules {
+	r := &rules{Game: g}
+	box := func(i, j int) int {
+		if i < 0 || i >= g.Rows || j < 0 || j >= g.Cols {
+			return -1
+		}
+		return i*g.Cols + j
+	}
+	for i := 0; i <= g.Rows; i++ {
+		for j := 0; j < g.Cols; j++ {
+			r.boxes = append(r.boxes, [2]int{box(i-1, j), box(i, j)})
+		}
+	}
+	for i := 0; i < g.Rows; i++ {
+		for j := 0; j <= g.Cols; j++ {
+			r.boxes = append(r.boxes, [2]int{box(i, j-1), box(i, j)})
+		}
+	}
+	return r
+}
+
+// line returns the index of the line of the move.
+func (r *rules) line(m Move) int {
+	if m.Vertical {
+		return (r.Rows+1)*r.Cols + m.I*(r.Cols+1) + m.J
+	}
+	return m.I*r.Cols + m.J
+}
+
+// move returns the move of the line of index k.
+func (r *rules) move(k int) Move {
+	if h := (r.Rows + 1) * r.Cols; k >= h {
+		k -= h
+		return Move{k / (r.Cols + 1), k % (r.Cols + 1), true}
+	}
+	return Move{k / r.Cols, k % r.Cols, false}
+}
+
+// sides returns the lines around box b.
+func (r *rules) sides(b int) [4]int {
+	i, j := b/r.Cols, b%r.Cols
+	return [4]int{
+		r.line(Move{i, j, false}),
+		r.line(Move{i + 1, j, false}),
+		r.line(Move{i, j, true}),
+		r.line(Move{i, j + 1, true}),
+	}
+}
+
+// Dim returns the number of rows and columns of boxes.
+func (s *State) Dim() (int, int) {
+	return s.Rows, s.Cols
+}
+
+// Get returns the string representation of the owner of box (i, j).
+func (s *State) Get(i, j int) string {
+	return s.Boxes[i*s.Cols+j].String()
+}
+
+// Line tells if the line from dot (i, j) to the right, or down if
+// vertical, is drawn.
+func (s *State) Line(i, j int, vertical bool) bool {
+	return s.Lines[s.line(Move{i, j, vertical})]
+}
+
+// drawn returns the number of lines drawn around box b.
+func (s *State) drawn(b int) (n int) {
+	for _, k := range s.sides(b) {
+		if s.Lines[k] {
+			n++
+		}
+	}
+	return
+}
+
+// Count returns the numbers of boxes owned by O and X.
+func (s *State) Count() (o, x int) {
+	for _, c := range s.Boxes {
+		switch c {
+		case O:
+			o++
+		case X:
+			x++
+		}
+	}
+	return
+}
+
+// MustPass tells if the player to move must pass, because the
+// other player completed a box and moves again.
+func (s *State) MustPass() bool {
+	return s.passing
+}
+
+// IsEnd tells if the game has ended, because all the boxes are
+// owned, or a player owns more than half of them.
+func (s *State) IsEnd() bool {
+	o, x := s.Count()
+	half := len(s.Boxes) / 2
+	return o > half || x > half || o+x == len(s.Boxes)
+}
+
+// Weights of the evaluation.
+const (
+	boxWeight   = 4 // of a box owned, or about to be taken
+	chainWeight = 6 // of the control of the long chains
+)
+
+// Eval returns the evaluation of the current state.
+// A player owning more than half of the boxes has won.  Otherwise
+// the state is evaluated by the boxes owned, those the player to move
+// can take at once, and the long chain rule.
+func (s *State) Eval() (eval game.Evaluation) {
+	o, x := s.Count()
+	half := len(s.Boxes) / 2
+	switch {
+	case o > half:
+		return game.Won
+	case x > half:
+		return game.Lost
+	case s.IsEnd():
+		return 0
+	}
+	eval = game.Evaluation(boxWeight * (o - x))
+	open, long := s.chains()
+	mover := s.Turn
+	if s.passing {
+		mover ^= O ^ X
+	}
+	sign := game.Evaluation(1)
+	if mover == X {
+		sign = -1
+	}
+	eval += sign * game.Evaluation(boxWeight*open)
+	if long > 0 {
+		// O, moving first, wants the dots and the long chains
+		// to add up to an even number, and X an odd one
+		if ((s.Rows+1)*(s.Cols+1)+long)%2 == 0 {
+			eval += chainWeight
+		} else {
+			eval -= chainWeight
+		}
+	}
+	return
+}
+
+// chains analyses the boxes not owned that have two or three sides
+// drawn, which are linked into chains by the lines not drawn between
+// them.  A chain with a box of three sides is open: the player to
+// move can take all its boxes.  It returns the number of boxes in
+// open chains, and the number of closed chains of at least three
+// boxes, which are long.
+func (s *State) chains() (open, long int) {
+	seen := make([]bool, len(s.Boxes))
+	var stack []int
+	for b, c := range s.Boxes {
+		if c != Empty || seen[b] || s.drawn(b) < 2 {
+			continue
+		}
+		size, isOpen := 0, false
+		seen[b] = true
+		stack = append(stack[:0], b)
+		for len(stack) > 0 {
+			a := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			size++
+			isOpen = isOpen || s.drawn(a) == 3
+			for _, k := range s.sides(a) {
+				if s.Lines[k] {
+					continue
+				}
+				for _, n := range s.boxes[k] {
+					if n >= 0 && !seen[n] && s.drawn(n) >= 2 {
+						seen[n] = true
+						stack = append(stack, n)
+					}
+				}
+			}
+		}
+		switch {
+		case isOpen:
+			open += size
+		case size >= 3:
+			long++
+		}
+	}
+	return
+}
+
+// Next returns all possible next states.
+func (s *State) Next() (nxt []game.State) {
+	for _, k := range s.Moves(nil) {
+		t := s.clone()
+		t.Play(k)
+		t.history = nil
+		nxt = append(nxt, t)
+	}
+	return
+}
+
+// Move returns the next state based on the move, which may be
+// a pass.  It returns nil if the move is not allowed.
+func (s *State) Move(m Move) *State {
+	var k int
+	switch {
+	case s.IsEnd():
+		return nil
+	case m == Pass:
+		if !s.passing {
+			return nil
+		}
+		k = len(s.Lines)
+	case s.passing || !s.contains(m):
+		return nil
+	default:
+		if k = s.line(m); s.Lines[k] {
+			return nil
+		}
+	}
+	t := s.clone()
+	t.Play(k)
+	t.history = nil
+	return t
+}
+
+// contains tells if the line of the move is on the grid.
+func (r *rules) contains(m Move) bool {
+	if m.I < 0 || m.J < 0 {
+		return false
+	}
+	if m.Vertical {
+		return m.I < r.Rows && m.J <= r.Cols
+	}
+	return m.I <= r.Rows && m.J < r.Cols
+}
+
+// Pass returns a new state after a player passes.
+func (s *State) Pass() *State {
+	return s.Move(Pass)
+}
+
+// clone returns a copy of s without the history.
+func (s *State) clone() *State {
+	t := *s
+	t.Lines = append([]bool(nil), s.Lines...)
+	t.Boxes = append([]Cell(nil), s.Boxes...)
+	t.history = nil
+	return &t
+}
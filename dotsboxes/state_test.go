@@ -0,0 +1,228 @@
+package dotsboxes
+
+import (
+	"context"
+	"github.com/z-rui/game"
+	"math/rand"
+	"testing"
+)
+
+// start returns the state at the start of g.
+func start(t *testing.T, g Game) *State {
+	s, err := g.NewState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// draw draws the lines in order from s.  A player completing a box
+// draws the next line, after the pass of the other player.
+func draw(t *testing.T, s *State, lines ...string) *State {
+	for _, str := range lines {
+		m, err := ParseMove(str)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.MustPass() {
+			s = s.Pass()
+		}
+		u := s.Move(m)
+		if u == nil {
+			t.Fatalf("line %v not allowed:\n%v", str, s.Lines)
+		}
+		s = u
+	}
+	return s
+}
+
+func TestPerft(t *testing.T) {
+	for _, c := range []struct {
+		g    Game
+		want []uint64
+	}{
+		// every order of the 4 lines, the last one ending the game
+		{Game{Rows: 1, Cols: 1}, []uint64{1, 4, 12, 24, 24, 0}},
+		// no box is completed before the fourth line
+		{Game{Rows: 2, Cols: 2}, []uint64{1, 12, 132, 1320, 11880, 94368}},
+	} {
+		s := start(t, c.g)
+		for depth, want := range c.want {
+			if got := game.Perft(s, uint(depth)); got != want {
+				t.Errorf("%+v depth %d: got %d, want %d", c.g, depth, got, want)
+			}
+			if got := game.Perft(struct{ game.State }{s}, uint(depth)); got != want {
+				t.Errorf("%+v depth %d without mover: got %d, want %d", c.g, depth, got, want)
+			}
+		}
+	}
+}
+
+func TestAnotherTurn(t *testing.T) {
+	s := draw(t, start(t, Standard), "A1-A2", "B1-B2", "A1-B1")
+	if s.MustPass() || s.Turn != X {
+		t.Fatalf("the turn is kept without a box")
+	}
+	// X completes the box at A1, and draws again
+	s = draw(t, s, "A2-B2")
+	if s.Boxes[0] != X || !s.MustPass() || s.Turn != O {
+		t.Fatalf("X does not own the box")
+	}
+	if s.Move(Move{2, 2, false}) != nil {
+		t.Errorf("O draws instead of passing")
+	}
+	if moves := s.Moves(nil); len(moves) != 1 || moves[0] != len(s.Lines) {
+		t.Errorf("got moves %v, want only the pass", moves)
+	}
+	u := s.Pass()
+	if u == nil || u.MustPass() || u.Turn != X || u.LastMove != Pass {
+		t.Fatalf("the pass does not give X the turn")
+	}
+	if u.Pass() != nil {
+		t.Errorf("X passes")
+	}
+	// a line completing no box gives the turn to O
+	if u = draw(t, u, "D1-D2"); u.MustPass() || u.Turn != O {
+		t.Errorf("X keeps the turn without a box")
+	}
+}
+
+func TestDoubleCross(t *testing.T) {
+	// the boxes at A1 and A2 have 3 sides each, and O draws
+	// the line between them
+	s := draw(t, start(t, Standard),
+		"A1-A2", "A2-A3", "B1-B2", "B2-B3", "A1-B1", "A3-B3", "A2-B2")
+	if o, x := s.Count(); o != 2 || x != 0 {
+		t.Fatalf("got %d, %d boxes, want 2, 0", o, x)
+	}
+	if s.Boxes[0] != O || s.Boxes[1] != O {
+		t.Errorf("O does not own both boxes: %v", s.Boxes)
+	}
+	// two boxes by one line are still one more turn
+	u := s.Pass()
+	if u == nil || u.MustPass() || u.Turn != O {
+		t.Fatalf("O does not draw again")
+	}
+	if u = draw(t, u, "D1-D2"); u.Turn != X || u.MustPass() {
+		t.Errorf("O draws a third time")
+	}
+}
+
+func TestScore(t *testing.T) {
+	// O and X take one box each
+	s := draw(t, start(t, Game{Rows: 1, Cols: 2}),
+		"A2-B2", "A1-B1", "A3-B3", "A1-A2", "B1-B2", "A2-A3", "B2-B3")
+	if o, x := s.Count(); o != 1 || x != 1 || !s.IsEnd() {
+		t.Fatalf("got %d, %d boxes, want 1, 1 at the end", o, x)
+	}
+	if s.MustPass() || len(s.Next()) != 0 || s.Eval() != 0 {
+		t.Errorf("drawn game: got %v", s.Eval())
+	}
+	// the double-cross takes both boxes and ends the game
+	s = draw(t, start(t, Game{Rows: 1, Cols: 2}),
+		"A1-A2", "B1-B2", "A2-A3", "B2-B3", "A1-B1", "A3-B3", "A2-B2")
+	if o, x := s.Count(); o != 2 || x != 0 || !s.IsEnd() || s.Eval() != game.Won {
+		t.Errorf("O has not won by %d, %d boxes: %v", o, x, s.Eval())
+	}
+	// more than half of the boxes ends the game
+	s = draw(t, start(t, Game{Rows: 1, Cols: 3}),
+		"A1-A2", "B1-B2", "A2-A3", "B2-B3", "A1-B1", "A2-B2", "A3-B3")
+	if o, x := s.Count(); o != 0 || x != 2 || !s.IsEnd() || s.Eval() != game.Lost {
+		t.Errorf("X has not won by %d, %d boxes: %v", o, x, s.Eval())
+	}
+	if s.MustPass() || len(s.Next()) != 0 || s.Move(Move{0, 3, true}) != nil {
+		t.Errorf("decided game goes on")
+	}
+}
+
+func TestEval(t *testing.T) {
+	// O gave a box away, which X can take
+	s := draw(t, start(t, Standard), "A1-A2", "B1-B2", "A1-B1")
+	if e := s.Eval(); e >= 0 {
+		t.Errorf("got %v, want negative", e)
+	}
+	if open, long := s.chains(); open != 1 || long != 0 {
+		t.Errorf("got %d open, %d long, want 1, 0", open, long)
+	}
+	// a closed chain of 3 boxes along the top row
+	s = draw(t, start(t, Standard), "A1-A2", "A2-A3", "A3-A4", "B1-B2", "B2-B3", "B3-B4")
+	if open, long := s.chains(); open != 0 || long != 1 {
+		t.Errorf("got %d open, %d long, want 0, 1", open, long)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	// X takes the box given away
+	s := draw(t, start(t, Standard), "A1-A2", "B1-B2", "A1-B1")
+	r, _ := game.SearchOf(context.Background(), s, true,
+		game.Options{MaxDepth: 3, Table: game.NewTable(16), PVS: true, Killers: true, History: true})
+	if r.Next == nil {
+		t.Fatal("no move found")
+	}
+	if r.Next.LastMove != (Move{0, 1, true}) {
+		t.Errorf("got %v, want A2-B2", r.Next.LastMove)
+	}
+	// the passes do not confuse the search of the whole game
+	s = start(t, Game{Rows: 1, Cols: 2})
+	_, want := game.MinMax(s, 20, false)
+	r, _ = game.SearchOf(context.Background(), s, false,
+		game.Options{Table: game.NewTable(16), PVS: true, Killers: true, History: true})
+	if r.Eval != want {
+		t.Errorf("1×2: got %v, want %v", r.Eval, want)
+	}
+}
+
+func TestDecided(t *testing.T) {
+	// the search stops at a decided game, as MinMax does
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 20; n++ {
+		s := start(t, Game{Rows: 2, Cols: 3})
+		for k := 0; k < 12 && !s.IsEnd(); k++ {
+			nxt := s.Next()
+			s = nxt[r.Intn(len(nxt))].(*State)
+		}
+		for depth := uint(1); depth <= 6; depth++ {
+			_, want := game.MinMax(s, depth, false)
+			res, err := game.Search(context.Background(), s, false,
+				game.Options{MaxDepth: depth, Table: game.NewTable(16), PVS: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Eval != want {
+				t.Errorf("depth %d: got %v, MinMax got %v\n%v", depth, res.Eval, want, s.Lines)
+			}
+		}
+	}
+}
+
+func TestLines(t *testing.T) {
+	s := start(t, Game{Rows: 2, Cols: 3})
+	if len(s.Lines) != 3*3+2*4 || s.MoveKey() != 0 {
+		t.Fatalf("got %d lines, move key %d", len(s.Lines), s.MoveKey())
+	}
+	// every line has its name, and the boxes on either side
+	for k := range s.Lines {
+		m := s.move(k)
+		if p, err := ParseMove(m.String()); err != nil || s.line(p) != k {
+			t.Errorf("line %d: %v parsed as %v, %v", k, m, p, err)
+		}
+		n := 0
+		for _, b := range s.boxes[k] {
+			if b >= 0 {
+				n++
+			}
+		}
+		edge := !m.Vertical && (m.I == 0 || m.I == 2) || m.Vertical && (m.J == 0 || m.J == 3)
+		if edge && n != 1 || !edge && n != 2 {
+			t.Errorf("line %v: %d boxes", m, n)
+		}
+	}
+	if m, err := ParseMove("b1-a1"); err != nil || m != (Move{0, 0, true}) {
+		t.Errorf("dots in reverse: got %v, %v", m, err)
+	}
+	for _, str := range []string{"", "A1", "A1-B2", "A1-A3", "J1-J2", "A1-A1"} {
+		if _, err := ParseMove(str); err == nil {
+			t.Errorf("%q parsed", str)
+		}
+	}
+}
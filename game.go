@@ -2,7 +2,13 @@
 // and a MinMax algorithm for finding optimal moves.
 package game
 
-import "math"
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
 
 // Evaluation is the number measuring the state of the game.
 // It is positive if the state is advantageous to a chosen player
@@ -13,11 +19,96 @@ type Evaluation int32
 
 const (
 	// Lost refers to the chosen player will definitely lose.
-	Lost Evaluation = math.MinInt32
+	Lost Evaluation = -Won
 	// Won refers to the chosen player will definitely win.
-	Won  Evaluation = math.MaxInt32
+	Won Evaluation = math.MaxInt32
 )
 
+// MaxPlies is the largest number of plies (moves by either player)
+// to the end of the game that an evaluation can tell.
+// Evaluations of states that are not won or lost must be
+// within (Lost+MaxPlies, Won-MaxPlies).
+const MaxPlies = 1 << 16
+
+// WinIn returns the evaluation of a state from which
+// the chosen player wins in the given number of plies.
+// WinIn(0) is Won.
+func WinIn(plies int) Evaluation {
+	return Won - Evaluation(plies)
+}
+
+// LossIn returns the evaluation of a state from which
+// the chosen player loses in the given number of plies.
+// LossIn(0) is Lost.
+func LossIn(plies int) Evaluation {
+	return Lost + Evaluation(plies)
+}
+
+// IsWin tells if the chosen player will definitely win.
+func (e Evaluation) IsWin() bool {
+	return e > Won-MaxPlies
+}
+
+// IsLoss tells if the chosen player will definitely lose.
+func (e Evaluation) IsLoss() bool {
+	return e < Lost+MaxPlies
+}
+
+// PliesToWin returns the number of plies before the chosen player
+// wins, or -1 if the chosen player will not definitely win.
+func (e Evaluation) PliesToWin() int {
+	if !e.IsWin() {
+		return -1
+	}
+	return int(Won - e)
+}
+
+// PliesToLoss returns the number of plies before the chosen player
+// loses, or -1 if the chosen player will not definitely lose.
+func (e Evaluation) PliesToLoss() int {
+	if !e.IsLoss() {
+		return -1
+	}
+	return int(e - Lost)
+}
+
+// String converts an evaluation to the string representation.
+func (e Evaluation) String() string {
+	switch {
+	case e.IsWin():
+		return fmt.Sprintf("win in %d", e.PliesToWin())
+	case e.IsLoss():
+		return fmt.Sprintf("loss in %d", e.PliesToLoss())
+	}
+	return strconv.Itoa(int(e))
+}
+
+// Later returns the evaluation of a state given that of its next
+// state e: a win or a loss becomes one ply farther.
+func (e Evaluation) Later() Evaluation {
+	switch {
+	case e.IsWin():
+		return e - 1
+	case e.IsLoss():
+		return e + 1
+	}
+	return e
+}
+
+// sooner is the inverse of Later.  It converts the bound of
+// an evaluation of a state to that of its next state.
+func sooner(e Evaluation) Evaluation {
+	switch {
+	case e == Won, e == Lost:
+		return e
+	case e.IsWin():
+		return e + 1
+	case e.IsLoss():
+		return e - 1
+	}
+	return e
+}
+
 // State represents an abstract state of the game.
 type State interface {
 	// Eval returns the evaluation of the current state.
@@ -29,56 +120,250 @@ type State interface {
 // MinMax is the algorithm to find an optimal move for a current state.
 // It finds the next state who will result in a minimum/maximum
 // evaluation after certain iterations.
+// Among winning moves, it prefers those winning sooner;
+// among losing moves, those losing later.
 func MinMax(s State, iterations uint, findMin bool) (next State, eval Evaluation) {
+	var sr searcher
+	return sr.minmax(s, iterations, findMin, Lost, Won)
+}
+
+// searcher keeps the bookkeeping of a single search.
+type searcher struct {
+	ctx      context.Context    // nil if the search cannot be cancelled
+	table    *Table             // nil if no transposition table is used
+	deadline time.Time          // zero if there is no time limit
+	depth    uint               // depth of the current iteration
+	pv       [][]State          // principal variation found at each ply
+	root     Mover              // copy of the root changed in place; nil if not used
+	moves    [][]int            // buffer of the moves at each ply, when root is used
+	mpv      [][]int            // principal variation of moves, when root is used
+	killers  [][killerSlots]int // killer moves at each ply; nil if not used
+	history  *[2][]uint64       // history scores of each side; nil if not used
+	nodes    uint64             // number of states visited
+	cutoffs  uint64             // number of α-β cutoffs
+	aborted  bool               // set when the search has to stop
+	deeper   bool               // set when a leaf is cut off by the depth limit
+}
+
+// checkInterval is the number of nodes visited between two
+// checks of the deadline and the cancellation.
+const checkInterval = 1024
+
+// stop counts a visited node and tells if the search should stop.
+func (sr *searcher) stop() bool {
+	if sr.aborted {
+		return true
+	}
+	sr.nodes++
+	if sr.nodes%checkInterval == 0 {
+		if sr.ctx != nil && sr.ctx.Err() != nil {
+			sr.aborted = true
+		}
+		if !sr.deadline.IsZero() && time.Now().After(sr.deadline) {
+			sr.aborted = true
+		}
+	}
+	return sr.aborted
+}
+
+// setPV sets the principal variation at the given ply,
+// which starts with next and continues with the one at the next ply.
+func (sr *searcher) setPV(ply uint, next State) {
+	pv := append(sr.pv[ply][:0], next)
+	if ply+1 < uint(len(sr.pv)) {
+		pv = append(pv, sr.pv[ply+1]...)
+	}
+	sr.pv[ply] = pv
+}
+
+// hash returns the hash of s if it can be looked up in the table.
+func (sr *searcher) hash(s State) (hash uint64, ok bool) {
+	if sr.table == nil {
+		return
+	}
+	if h, isHasher := s.(Hasher); isHasher {
+		return h.Hash(), true
+	}
+	return
+}
+
+// probe looks up the state in the transposition table,
+// which has n next states.  It returns the index of the best
+// next state stored in the table as first, or -1 if there is none.
+// If the stored result settles the search within (α, β),
+// it also returns its evaluation and done = true.
+func (sr *searcher) probe(hash uint64, n int, iterations uint, α, β Evaluation) (first int, eval Evaluation, done bool) {
+	e, ok := sr.table.probe(hash)
+	if !ok || e.best < 0 || e.best >= n {
+		return -1, 0, false
+	}
+	if e.depth >= iterations &&
+		(e.bound == Exact ||
+			e.bound == LowerBound && e.eval >= β ||
+			e.bound == UpperBound && e.eval <= α) {
+		if e.depth != unlimited {
+			sr.deeper = true
+		}
+		return e.best, e.eval, true
+	}
+	return e.best, 0, false
+}
+
+// record saves the result of a search into the transposition table.
+// best is the index of the best next state in nxt,
+// as reordered by arrange with the returned perm.
+func (sr *searcher) record(hash uint64, iterations uint, complete bool, eval Evaluation, bound Bound, best int, perm []int) {
+	if perm != nil {
+		best = perm[best]
+	}
+	if complete {
+		iterations = unlimited
+	}
+	sr.table.store(hash, iterations, eval, bound, best)
+}
+
+// begin prepares the searcher for an iteration of the given depth.
+func (sr *searcher) begin(depth uint) {
+	sr.depth = depth
+	for uint(len(sr.pv)) <= depth {
+		sr.pv = append(sr.pv, nil)
+	}
+	for sr.killers != nil && uint(len(sr.killers)) <= depth {
+		sr.killers = append(sr.killers, [killerSlots]int{noKey, noKey})
+	}
+}
+
+func (sr *searcher) minmax(s State, iterations uint, findMin bool, α, β Evaluation) (next State, eval Evaluation) {
+	sr.begin(iterations)
 	if findMin {
-		return min(s, iterations, Lost, Won)
+		return sr.min(s, iterations, α, β)
 	} else {
-		return max(s, iterations, Lost, Won)
+		return sr.max(s, iterations, α, β)
 	}
 }
 
-func min(s State, iterations uint, α, β Evaluation) (next State, eval Evaluation) {
+func (sr *searcher) min(s State, iterations uint, α, β Evaluation) (next State, eval Evaluation) {
+	if sr.stop() {
+		return
+	}
+	ply := sr.depth - iterations
 	nxt := s.Next()
 	if iterations == 0 || len(nxt) == 0 {
+		if len(nxt) != 0 {
+			sr.deeper = true
+		}
+		sr.pv[ply] = sr.pv[ply][:0]
 		eval = s.Eval()
 		return
 	}
+	hash, hashed := sr.hash(s)
+	first := -1
+	if hashed {
+		var done bool
+		if first, eval, done = sr.probe(hash, len(nxt), iterations, α, β); done {
+			next = nxt[first]
+			sr.pv[ply] = append(sr.pv[ply][:0], next)
+			return
+		}
+	}
+	perm := sr.arrange(nxt, ply, first, true)
+	deeper, β0, best := sr.deeper, β, 0
+	sr.deeper = false
 	eval = Won
-	for _, t := range nxt {
-		_, e := max(t, iterations-1, α, β)
+	for i, t := range nxt {
+		_, e := sr.max(t, iterations-1, sooner(α), sooner(β))
+		e = e.Later()
+		if sr.aborted {
+			return
+		}
 		if next == nil || e < eval {
 			next = t
 			eval = e
+			best = i
+			sr.setPV(ply, t)
 			if e < β {
 				β = e
 				if α >= β {
+					sr.reward(t, ply, iterations, true)
+					sr.cutoffs++
 					break
 				}
 			}
 		}
 	}
+	if hashed {
+		bound := Exact
+		switch {
+		case eval >= β0:
+			bound = LowerBound
+		case eval <= α:
+			bound = UpperBound
+		}
+		sr.record(hash, iterations, !sr.deeper, eval, bound, best, perm)
+	}
+	sr.deeper = sr.deeper || deeper
 	return
 }
 
-func max(s State, iterations uint, α, β Evaluation) (next State, eval Evaluation) {
+func (sr *searcher) max(s State, iterations uint, α, β Evaluation) (next State, eval Evaluation) {
+	if sr.stop() {
+		return
+	}
+	ply := sr.depth - iterations
 	nxt := s.Next()
 	if iterations == 0 || len(nxt) == 0 {
+		if len(nxt) != 0 {
+			sr.deeper = true
+		}
+		sr.pv[ply] = sr.pv[ply][:0]
 		eval = s.Eval()
 		return
 	}
+	hash, hashed := sr.hash(s)
+	first := -1
+	if hashed {
+		var done bool
+		if first, eval, done = sr.probe(hash, len(nxt), iterations, α, β); done {
+			next = nxt[first]
+			sr.pv[ply] = append(sr.pv[ply][:0], next)
+			return
+		}
+	}
+	perm := sr.arrange(nxt, ply, first, false)
+	deeper, α0, best := sr.deeper, α, 0
+	sr.deeper = false
 	eval = Lost
-	for _, t := range nxt {
-		_, e := min(t, iterations-1, α, β)
+	for i, t := range nxt {
+		_, e := sr.min(t, iterations-1, sooner(α), sooner(β))
+		e = e.Later()
+		if sr.aborted {
+			return
+		}
 		if next == nil || e > eval {
 			next = t
 			eval = e
+			best = i
+			sr.setPV(ply, t)
 			if e > α {
 				α = e
 				if α >= β {
+					sr.reward(t, ply, iterations, false)
+					sr.cutoffs++
 					break
 				}
 			}
 		}
 	}
+	if hashed {
+		bound := Exact
+		switch {
+		case eval <= α0:
+			bound = UpperBound
+		case eval >= β:
+			bound = LowerBound
+		}
+		sr.record(hash, iterations, !sr.deeper, eval, bound, best, perm)
+	}
+	sr.deeper = sr.deeper || deeper
 	return
 }
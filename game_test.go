@@ -0,0 +1,21 @@
+package game
+
+import "testing"
+
+func TestLater(t *testing.T) {
+	for _, c := range []struct{ e, want Evaluation }{
+		{Won, WinIn(1)},
+		{WinIn(5), WinIn(6)},
+		{Lost, LossIn(1)},
+		{LossIn(5), LossIn(6)},
+		{0, 0},
+		{-42, -42},
+	} {
+		if got := c.e.Later(); got != c.want {
+			t.Errorf("%v: got %v, want %v", c.e, got, c.want)
+		}
+		if got := sooner(c.e.Later()); got != c.e {
+			t.Errorf("%v: sooner of Later is %v", c.e, got)
+		}
+	}
+}
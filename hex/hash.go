@@ -0,0 +1,36 @@
+package hex
+
+import "math/rand"
+
+// zobristX is the Zobrist key for X's turn.
+var zobristX uint64
+
+// zobristCells are the Zobrist keys for O and X at each cell,
+// of the largest board in row-major order.
+var zobristCells [MaxSize * MaxSize][2]uint64
+
+// Zobrist keys generation
+func init() {
+	r := rand.New(rand.NewSource(MaxSize))
+	for k := range zobristCells {
+		zobristCells[k][0] = r.Uint64()
+		zobristCells[k][1] = r.Uint64()
+	}
+	zobristX = r.Uint64()
+}
+
+// Hash returns the Zobrist hash of the state.
+func (s *State) Hash() (h uint64) {
+	for k, c := range s.Board {
+		switch c {
+		case O:
+			h ^= zobristCells[k][0]
+		case X:
+			h ^= zobristCells[k][1]
+		}
+	}
+	if s.Turn == X {
+		h ^= zobristX
+	}
+	return
+}
@@ -0,0 +1,66 @@
+package hex
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Move represents a position on the board, or the swap.
+type Move struct {
+	I, J int
+}
+
+var invalidMove = Move{-1, -1}
+
+// SwapMove is the move of X taking the first move of O.
+var SwapMove = Move{MaxSize, MaxSize}
+
+// String converts a Move to the string representation,
+// such as "C11" for the row C and the column 11, or "swap".
+func (m Move) String() string {
+	switch {
+	case m == SwapMove:
+		return "swap"
+	case !m.Valid():
+		return "(none)"
+	}
+	return string(rune('A'+m.I)) + strconv.Itoa(m.J+1)
+}
+
+// ParseMove converts the string representation of a move back
+// to a Move.  It does not tell if the move is on a particular board.
+func ParseMove(str string) (Move, error) {
+	if strings.EqualFold(str, "swap") {
+		return SwapMove, nil
+	}
+	if len(str) < 2 {
+		return invalidMove, errors.New("hex: bad move " + str)
+	}
+	c := str[0]
+	if 'a' <= c && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	j, err := strconv.Atoi(str[1:])
+	if c < 'A' || c >= 'A'+MaxSize || err != nil || j < 1 || j > MaxSize {
+		return invalidMove, errors.New("hex: bad move " + str)
+	}
+	return Move{int(c - 'A'), j - 1}, nil
+}
+
+// Valid tells if the move is a position on the largest board.
+func (m Move) Valid() bool {
+	return 0 <= m.I && m.I < MaxSize && 0 <= m.J && m.J < MaxSize
+}
+
+// MoveKey returns the index of the cell where the last move was placed,
+// or N*N for the swap.  It is 0 at the start, where there is no last move.
+func (s *State) MoveKey() int {
+	switch {
+	case s.LastMove == SwapMove:
+		return s.N * s.N
+	case !s.LastMove.Valid():
+		return 0
+	}
+	return s.LastMove.I*s.N + s.LastMove.J
+}
@@ -0,0 +1,92 @@
+package hex
+
+import "github.com/z-rui/game"
+
+// undo is what Undo needs to take back a move.
+type undo struct {
+	last    Move
+	changes int // in the log of the sets before the move
+}
+
+// Moves appends the moves allowed in the current state to moves,
+// in the same order as Next.  A move is the index of the cell, i*N+j,
+// starting from the center, or N*N for the swap, which comes last.
+func (s *State) Moves(moves []int) []int {
+	if s.IsEnd() {
+		return moves
+	}
+	for _, k := range s.order {
+		if s.Board[k] == Empty {
+			moves = append(moves, k)
+		}
+	}
+	if s.canSwap() {
+		moves = append(moves, s.N*s.N)
+	}
+	return moves
+}
+
+// Play makes the move in place.
+func (s *State) Play(move int) {
+	s.history = append(s.history, undo{s.LastMove, len(s.log)})
+	n := s.N
+	if move == n*n {
+		// the only piece is replaced, so the sets start over
+		m := s.LastMove
+		s.Board[m.I*n+m.J] = Empty
+		for k := range s.parent {
+			if int(s.parent[k]) != k || s.rank[k] != 0 {
+				s.set(k, k, 0)
+			}
+		}
+		s.place(m.J*n + m.I)
+		s.LastMove = SwapMove
+	} else {
+		s.place(move)
+		s.LastMove = Move{move / n, move % n}
+	}
+	s.Turn ^= O ^ X
+	s.Plies++
+}
+
+// place places a piece of the player to move at cell k,
+// connecting it to the neighbors and the sides.
+func (s *State) place(k int) {
+	s.Board[k] = s.Turn
+	for _, e := range s.edges[k] {
+		if e < 0 {
+			continue
+		}
+		// O only connects the top and the bottom, X the left and the right
+		if (s.Turn == O) == (int(e)-s.N*s.N < left) {
+			s.union(k, int(e))
+		}
+	}
+	for _, nb := range s.neighbors[k] {
+		if s.Board[nb] == s.Turn {
+			s.union(k, nb)
+		}
+	}
+}
+
+// Undo takes back the last move played.
+func (s *State) Undo() {
+	n := len(s.history) - 1
+	u := s.history[n]
+	s.history = s.history[:n]
+	s.rollback(u.changes)
+	if m := s.LastMove; m == SwapMove {
+		s.Board[u.last.J*s.N+u.last.I] = Empty
+		s.Board[u.last.I*s.N+u.last.J] = O
+	} else {
+		s.Board[m.I*s.N+m.J] = Empty
+	}
+	s.LastMove = u.last
+	s.Turn ^= O ^ X
+	s.Plies--
+}
+
+// Clone returns a copy of the current state.
+func (s *State) Clone() game.Mover {
+	return s.clone()
+}
@@ -0,0 +1,75 @@
+package hex
+
+// sets is a union-find structure of the cells and the sides of the
+// board, which tells the pieces connected to each other.  It does not
+// compress the paths, so that finding does not change it, but it links
+// by rank to keep the paths short.  The changes are logged to be undone.
+type sets struct {
+	parent []int16
+	rank   []uint8
+	log    []change
+}
+
+// change is what a union changed in a node.
+type change struct {
+	node   int16
+	parent int16
+	rank   uint8
+}
+
+// newSets returns n nodes, each in a set by itself.
+func newSets(n int) sets {
+	u := sets{parent: make([]int16, n), rank: make([]uint8, n)}
+	for k := range u.parent {
+		u.parent[k] = int16(k)
+	}
+	return u
+}
+
+// clone returns a copy of u without the log.
+func (u *sets) clone() sets {
+	return sets{
+		parent: append([]int16(nil), u.parent...),
+		rank:   append([]uint8(nil), u.rank...),
+	}
+}
+
+// find returns the root of the set of node k.
+func (u *sets) find(k int) int {
+	for int(u.parent[k]) != k {
+		k = int(u.parent[k])
+	}
+	return k
+}
+
+// union merges the sets of nodes a and b.
+func (u *sets) union(a, b int) {
+	a, b = u.find(a), u.find(b)
+	if a == b {
+		return
+	}
+	if u.rank[a] < u.rank[b] {
+		a, b = b, a
+	}
+	u.set(b, a, u.rank[b])
+	if u.rank[a] == u.rank[b] {
+		u.set(a, a, u.rank[a]+1)
+	}
+}
+
+// set changes the parent and the rank of node k, logging the change.
+func (u *sets) set(k, parent int, rank uint8) {
+	u.log = append(u.log, change{int16(k), u.parent[k], u.rank[k]})
+	u.parent[k] = int16(parent)
+	u.rank[k] = rank
+}
+
+// rollback undoes the changes after the first n in the log.
+func (u *sets) rollback(n int) {
+	for i := len(u.log) - 1; i >= n; i-- {
+		c := u.log[i]
+		u.parent[c.node] = c.parent
+		u.rank[c.node] = c.rank
+	}
+	u.log = u.log[:n]
+}
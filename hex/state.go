@@ -0,0 +1,409 @@
+// Package hex implements the Hex game: two players take turns to place
+// their pieces on a rhombus of hexagonal cells, and the first to connect
+// their two sides of the board wins.  O connects the first and the last
+// rows, and X the first and the last columns.  The game cannot be drawn.
+package hex
+
+import (
+	"errors"
+	"github.com/z-rui/game"
+)
+
+// Cell represents a cell of the board.
+// It has three states: Empty, O and X.
+type Cell uint8
+
+const (
+	Empty Cell = iota
+	O
+	X
+)
+
+// String converts a cell to the string representation.
+func (c Cell) String() string {
+	switch c {
+	case O:
+		return "O"
+	case X:
+		return "X"
+	default:
+		return "."
+	}
+}
+
+// MinSize and MaxSize are the limits of the size of the board.
+const (
+	MinSize = 2
+	MaxSize = 19
+)
+
+// Game describes a game of Hex.
+type Game struct {
+	N int // rows and columns of the board
+	// Swap allows X to answer the first move of O by taking it:
+	// the piece of O is replaced by a piece of X at the mirror
+	// image of the cell across the long diagonal, and O moves next.
+	// It makes a strong first move a bad idea.
+	Swap bool
+}
+
+// Standard is the usual game on an 11×11 board with the swap rule.
+var Standard = Game{N: 11, Swap: true}
+
+// rules is what the states of a game share.
+type rules struct {
+	Game
+	order     []int      // cells by the distance from the center
+	neighbors [][]int    // of each cell
+	edges     [][2]int16 // nodes of the sides each cell touches, or -1
+}
+
+// State represents the current state of the game.
+type State struct {
+	*rules
+	Board    []Cell // cells in row-major order
+	LastMove Move
+	Turn     Cell   // must be O or X
+	Plies    int    // number of moves made
+	sets            // pieces connected to each other and to the sides
+	history  []undo // to take back the moves played by Play
+}
+
+// NewState returns a new state at the start of the game.
+func (g Game) NewState() (*State, error) {
+	if g.N < MinSize || g.N > MaxSize {
+		return nil, errors.New("hex: bad board size")
+	}
+	s := &State{
+		rules:    newRules(g),
+		Board:    make([]Cell, g.N*g.N),
+		LastMove: invalidMove,
+		Turn:     O,
+		sets:     newSets(g.N*g.N + sides),
+	}
+	return s, nil
+}
+
+// The sides of the board are nodes of the sets after the cells.
+const (
+	top = iota
+	bottom
+	left
+	right
+	sides
+)
+
+// newRules prepares the rules of the game.
+func newRules(g Game) *rules {
+	n := g.N
+	r := &rules{
+		Game:      g,
+		neighbors: make([][]int, n*n),
+		edges:     make([][2]int16, n*n),
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			k := i*n + j
+			for _, d := range directions {
+				if ni, nj := i+d[0], j+d[1]; 0 <= ni && ni < n && 0 <= nj && nj < n {
+					r.neighbors[k] = append(r.neighbors[k], ni*n+nj)
+				}
+			}
+			r.edges[k] = [2]int16{-1, -1}
+			switch {
+			case i == 0:
+				r.edges[k][0] = int16(n*n + top)
+			case i == n-1:
+				r.edges[k][0] = int16(n*n + bottom)
+			}
+			switch {
+			case j == 0:
+				r.edges[k][1] = int16(n*n + left)
+			case j == n-1:
+				r.edges[k][1] = int16(n*n + right)
+			}
+			r.order = append(r.order, k)
+		}
+	}
+	// the center is usually the best place to start searching
+	dist := make([]int, n*n)
+	for k := range dist {
+		dist[k] = distance(k/n*2, k%n*2, n-1, n-1)
+	}
+	sortBy(r.order, dist)
+	return r
+}
+
+// directions are the six neighbors of a cell.  Each row is drawn half
+// a cell to the right of the row above, so that (i, j) touches
+// (i-1, j) and (i-1, j+1) above, and (i+1, j-1) and (i+1, j) below.
+var directions = [6][2]int{{-1, 0}, {-1, 1}, {0, -1}, {0, 1}, {1, -1}, {1, 0}}
+
+// distance returns the number of steps between two cells.
+func distance(i1, j1, i2, j2 int) int {
+	di, dj := i2-i1, j2-j1
+	if di*dj < 0 {
+		if abs(di) > abs(dj) {
+			return abs(di)
+		}
+		return abs(dj)
+	}
+	return abs(di) + abs(dj)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// sortBy sorts a by the keys, keeping the order of equal keys.
+func sortBy(a []int, keys []int) {
+	for i := 1; i < len(a); i++ {
+		for k := i; k > 0 && keys[a[k]] < keys[a[k-1]]; k-- {
+			a[k], a[k-1] = a[k-1], a[k]
+		}
+	}
+}
+
+// Dim returns the dimension of the board
+func (s *State) Dim() (int, int) {
+	return s.N, s.N
+}
+
+// Get returns the string representation at (i, j)
+func (s *State) Get(i, j int) string {
+	return s.Board[i*s.N+j].String()
+}
+
+// Winner returns the player who has connected the sides, or Empty.
+func (s *State) Winner() Cell {
+	n := s.N * s.N
+	switch {
+	case s.find(n+top) == s.find(n+bottom):
+		return O
+	case s.find(n+left) == s.find(n+right):
+		return X
+	}
+	return Empty
+}
+
+// IsEnd tells if the game has ended.
+func (s *State) IsEnd() bool {
+	return s.Winner() != Empty
+}
+
+// potentialWeight is what a step closer to a connection is worth
+// in the evaluation, more than any count of the best cells.
+const potentialWeight = MaxSize*MaxSize + 1
+
+// Eval returns the evaluation of the current state.
+// A state not ended is evaluated by the potentials of the players:
+// the least sum of the two-distances from a player's sides to an
+// empty cell, which tells how far the player is from a connection
+// that the other player cannot simply block.  The number of cells
+// of the least sum breaks ties.
+func (s *State) Eval() (eval game.Evaluation) {
+	switch s.Winner() {
+	case O:
+		return game.Won
+	case X:
+		return game.Lost
+	}
+	po, co := s.potential(O)
+	px, cx := s.potential(X)
+	return game.Evaluation(potentialWeight*(px-po) + co - cx)
+}
+
+// potential returns the potential of the player and the number of
+// empty cells where it is reached.
+func (s *State) potential(player Cell) (p, count int) {
+	n := s.N * s.N
+	first, second := n+top, n+bottom
+	if player == X {
+		first, second = n+left, n+right
+	}
+	d1 := s.twoDistances(player, first)
+	d2 := s.twoDistances(player, second)
+	p = 2 * infinity
+	for k, c := range s.Board {
+		if c != Empty {
+			continue
+		}
+		switch d := d1[k] + d2[k]; {
+		case d < p:
+			p, count = d, 1
+		case d == p:
+			count++
+		}
+	}
+	return
+}
+
+// infinity is more than any two-distance.
+const infinity = MaxSize * MaxSize
+
+// twoDistances returns the two-distance of each cell from the side
+// for the player.  The two-distance of an empty cell touching the side,
+// or a group of the player's pieces connected to the side, is 1;
+// otherwise it is 1 more than the second least two-distance of its
+// neighbors, as the other player can always block the best one.
+// The pieces of a group share the least two-distance of their
+// neighbors, as moving through them costs nothing, and count once.
+func (s *State) twoDistances(player Cell, side int) []int {
+	d := make([]int, len(s.Board))
+	group := make([]int, len(s.parent)) // two-distance of each group by the root
+	root := make([]int, len(s.Board))
+	for k := range group {
+		group[k] = infinity
+	}
+	for k := range d {
+		d[k] = infinity
+		if s.Board[k] == player {
+			root[k] = s.find(k)
+		}
+	}
+	sideRoot := s.find(side)
+	group[sideRoot] = 0
+	// the two-distances are found in increasing order, so that those
+	// less than the current level are final, and a cell can only reach
+	// the level next to a cell or a group of the level before
+	var found, next []int
+	for _, k := range s.order {
+		if s.Board[k] == Empty && s.reaches(player, k, side, sideRoot, 1, d, group, root) {
+			found = append(found, k)
+		}
+	}
+	mark := make([]int, len(s.Board)) // the last level a cell is checked for
+	for level := 1; len(found) > 0; level++ {
+		for _, k := range found {
+			d[k] = level
+		}
+		next = next[:0]
+		check := func(k int) {
+			for _, nb := range s.neighbors[k] {
+				if s.Board[nb] == Empty && d[nb] == infinity && mark[nb] != level {
+					mark[nb] = level
+					if s.reaches(player, nb, side, sideRoot, level+1, d, group, root) {
+						next = append(next, nb)
+					}
+				}
+			}
+		}
+		// the groups next to the cells found join the level
+		grouped := false
+		for _, k := range found {
+			for _, nb := range s.neighbors[k] {
+				if s.Board[nb] == player && group[root[nb]] > level {
+					group[root[nb]] = level
+					grouped = true
+				}
+			}
+		}
+		for _, k := range found {
+			check(k)
+		}
+		if grouped {
+			for k, c := range s.Board {
+				if c == player && group[root[k]] == level {
+					check(k)
+				}
+			}
+		}
+		found, next = next, found
+	}
+	for k, c := range s.Board {
+		if c == player {
+			d[k] = group[root[k]]
+		}
+	}
+	return d
+}
+
+// reaches tells if the two-distance of the empty cell k is the level,
+// when the two-distances less than the level are known.
+func (s *State) reaches(player Cell, k, side, sideRoot, level int, d, group, root []int) bool {
+	if e := s.edges[k]; int(e[0]) == side || int(e[1]) == side {
+		return true
+	}
+	var seen [6]int // groups counted
+	n, count := 0, 0
+	for _, nb := range s.neighbors[k] {
+		switch s.Board[nb] {
+		case player:
+			r := root[nb]
+			if r == sideRoot {
+				return true
+			}
+			dup := false
+			for _, g := range seen[:n] {
+				dup = dup || g == r
+			}
+			if dup {
+				continue
+			}
+			seen[n] = r
+			n++
+			if group[r] < level {
+				count++
+			}
+		case Empty:
+			if d[nb] < level {
+				count++
+			}
+		}
+	}
+	return count >= 2
+}
+
+// Next returns all possible next states.
+func (s *State) Next() (nxt []game.State) {
+	for _, k := range s.Moves(nil) {
+		t := s.clone()
+		t.Play(k)
+		t.history = nil
+		nxt = append(nxt, t)
+	}
+	return
+}
+
+// Move returns the next state based on the move, which may be
+// the swap.  It returns nil if the move is not allowed.
+func (s *State) Move(m Move) *State {
+	if s.IsEnd() {
+		return nil
+	}
+	var k int
+	switch {
+	case m == SwapMove:
+		if !s.canSwap() {
+			return nil
+		}
+		k = s.N * s.N
+	case !m.Valid() || m.I >= s.N || m.J >= s.N:
+		return nil
+	default:
+		if k = m.I*s.N + m.J; s.Board[k] != Empty {
+			return nil
+		}
+	}
+	t := s.clone()
+	t.Play(k)
+	t.history = nil
+	return t
+}
+
+// canSwap tells if the swap is allowed.
+func (s *State) canSwap() bool {
+	return s.Swap && s.Plies == 1
+}
+
+// clone returns a copy of s without the history.
+func (s *State) clone() *State {
+	t := *s
+	t.Board = append([]Cell(nil), s.Board...)
+	t.sets = s.sets.clone()
+	t.history = nil
+	return &t
+}
@@ -0,0 +1,205 @@
+package hex
+
+import (
+	"context"
+	"github.com/z-rui/game"
+	"math/rand"
+	"testing"
+)
+
+// play plays the moves from the start of g.
+func play(t *testing.T, g Game, moves ...string) *State {
+	s, err := g.NewState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, str := range moves {
+		m, err := ParseMove(str)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s = s.Move(m); s == nil {
+			t.Fatalf("move %v not allowed", m)
+		}
+	}
+	return s
+}
+
+func TestPerft(t *testing.T) {
+	// no one can connect the sides of the 3×3 board in 4 plies
+	for _, c := range []struct {
+		g    Game
+		want []uint64
+	}{
+		{Game{N: 3}, []uint64{1, 9, 72, 504, 3024, 15120}},
+		// X may answer the first move with the swap
+		{Game{N: 3, Swap: true}, []uint64{1, 9, 81, 576, 3528, 18144}},
+	} {
+		s := play(t, c.g)
+		for depth, want := range c.want {
+			if got := game.Perft(s, uint(depth)); got != want {
+				t.Errorf("%+v depth %d: got %d, want %d", c.g, depth, got, want)
+			}
+			if got := game.Perft(struct{ game.State }{s}, uint(depth)); got != want {
+				t.Errorf("%+v depth %d without mover: got %d, want %d", c.g, depth, got, want)
+			}
+		}
+	}
+}
+
+func TestMoveKey(t *testing.T) {
+	g := Game{N: 5, Swap: true}
+	for _, c := range []struct {
+		moves []string
+		want  int
+	}{
+		{nil, 0},
+		{[]string{"B3"}, 1*5 + 2},
+		{[]string{"B3", "swap"}, 5 * 5},
+	} {
+		if got := play(t, g, c.moves...).MoveKey(); got != c.want {
+			t.Errorf("%v: got key %d, want %d", c.moves, got, c.want)
+		}
+	}
+}
+
+func TestWinner(t *testing.T) {
+	g := Game{N: 3}
+	for _, c := range []struct {
+		moves []string
+		want  Cell
+	}{
+		{[]string{"A1", "A2", "B1", "B2", "C1"}, O},
+		{[]string{"A2", "A1", "B1", "B2", "C1"}, O}, // A2 touches B1
+		{[]string{"A1", "A2", "B2", "A3", "C2"}, Empty},
+		{[]string{"A1", "B1", "A2", "B2", "C3", "B3"}, X},
+		{[]string{"A3", "C1", "B3", "B2", "C2", "A3"}, Empty},
+	} {
+		s := play(t, g, c.moves[:len(c.moves)-1]...)
+		m, _ := ParseMove(c.moves[len(c.moves)-1])
+		if s.Move(m) == nil {
+			if c.want != Empty {
+				t.Fatalf("%v: last move not allowed", c.moves)
+			}
+			continue
+		}
+		s = s.Move(m)
+		if got := s.Winner(); got != c.want {
+			t.Errorf("%v: got winner %v, want %v", c.moves, got, c.want)
+		}
+		if s.IsEnd() != (c.want != Empty) {
+			t.Errorf("%v: wrong end", c.moves)
+		}
+	}
+}
+
+func TestNoDraw(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		s := play(t, Game{N: 1 + MinSize + i%8})
+		// fill the board at random, ignoring the end
+		for _, k := range r.Perm(len(s.Board)) {
+			s.Play(k)
+		}
+		n := s.N * s.N
+		o := s.find(n+top) == s.find(n+bottom)
+		x := s.find(n+left) == s.find(n+right)
+		if o == x {
+			t.Fatalf("full board: O connected %v, X connected %v", o, x)
+		}
+	}
+}
+
+func TestUndo(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	s := play(t, Standard)
+	start := s.clone()
+	var moves []int
+	for !s.IsEnd() {
+		m := s.Moves(nil)
+		k := m[r.Intn(len(m))]
+		s.Play(k)
+		moves = append(moves, k)
+	}
+	for range moves {
+		s.Undo()
+	}
+	if s.Hash() != start.Hash() || s.Plies != 0 || s.LastMove != invalidMove {
+		t.Errorf("board not restored")
+	}
+	for k := range s.parent {
+		if s.parent[k] != start.parent[k] || s.rank[k] != start.rank[k] {
+			t.Fatalf("sets not restored at %d", k)
+		}
+	}
+}
+
+func TestSwap(t *testing.T) {
+	g := Game{N: 5, Swap: true}
+	s := play(t, g, "A2", "swap")
+	if s.Get(1, 0) != "X" || s.Get(0, 1) != "." || s.Turn != O || s.LastMove != SwapMove {
+		t.Errorf("piece not swapped")
+	}
+	if got := len(s.Moves(nil)); got != 24 {
+		t.Errorf("got %d moves after the swap, want 24", got)
+	}
+	for _, moves := range [][]string{{"swap"}, {"A2", "B2", "swap"}} {
+		s := play(t, g, moves[:len(moves)-1]...)
+		if s.Move(SwapMove) != nil {
+			t.Errorf("%v allowed", moves)
+		}
+	}
+	if play(t, Game{N: 5}, "A2").Move(SwapMove) != nil {
+		t.Errorf("swap allowed without the swap rule")
+	}
+	u := play(t, g, "A2")
+	u.Play(u.Moves(nil)[24])
+	u.Undo()
+	if u.Get(0, 1) != "O" || u.Get(1, 0) != "." || u.Turn != X {
+		t.Errorf("swap not undone")
+	}
+}
+
+func TestEval(t *testing.T) {
+	g := Game{N: 7}
+	if e := play(t, g, "D4", "A1").Eval(); e <= 0 {
+		t.Errorf("center vs corner: got %v, want positive", e)
+	}
+	// two O pieces in a bridge are as good as connected
+	bridge, apart := play(t, g, "C4", "A1", "E3", "A2"), play(t, g, "C4", "A1", "F4", "A2")
+	if bridge.Eval() <= apart.Eval() {
+		t.Errorf("bridge not better: %v <= %v", bridge.Eval(), apart.Eval())
+	}
+	if e := play(t, Game{N: 3}, "A1", "A2", "B1", "B2", "C1").Eval(); e != game.Won {
+		t.Errorf("connected: got %v, want %v", e, game.Won)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	// the first player wins without the swap rule
+	s := play(t, Game{N: 3})
+	if _, eval := game.MinMax(s, 9, false); !eval.IsWin() {
+		t.Errorf("3×3: got %v, want a win", eval)
+	}
+	// O wins by the last empty cell of the column
+	s = play(t, Game{N: 5}, "A3", "A1", "B3", "B1", "C3", "C1", "D3", "D1")
+	r, _ := game.SearchOf(context.Background(), s, false,
+		game.Options{MaxDepth: 3, Table: game.NewTable(16), PVS: true})
+	if r.Next == nil || r.Next.LastMove != (Move{4, 2}) && r.Next.LastMove != (Move{4, 1}) {
+		t.Errorf("got %v, want a win", r.Next)
+	}
+}
+
+func TestParseMove(t *testing.T) {
+	for _, str := range []string{"A1", "S19", "swap"} {
+		m, err := ParseMove(str)
+		if err != nil || m.String() != str {
+			t.Errorf("%q: got %v, %v", str, m, err)
+		}
+	}
+	for _, str := range []string{"", "A", "T1", "A20", "A0"} {
+		if _, err := ParseMove(str); err == nil {
+			t.Errorf("%q parsed", str)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package kalah
+
+import "math/rand"
+
+// Zobrist keys for hashing a state.
+var (
+	zobristSeeds [2*MaxPits + 2][2*MaxPits*MaxSeeds + 1]uint64 // for the seeds in each pit or store
+	zobristX     uint64                                        // for X's turn
+	zobristPass  uint64                                        // for a player who must pass
+)
+
+// Zobrist keys generation
+func init() {
+	r := rand.New(rand.NewSource(MaxPits))
+	for i := range zobristSeeds {
+		for n := range zobristSeeds[i] {
+			zobristSeeds[i][n] = r.Uint64()
+		}
+	}
+	zobristX = r.Uint64()
+	zobristPass = r.Uint64()
+}
+
+// Hash returns the Zobrist hash of the state.
+func (s *State) Hash() (h uint64) {
+	for i, n := range s.Board {
+		h ^= zobristSeeds[i][n]
+	}
+	if s.Turn == X {
+		h ^= zobristX
+	}
+	if s.passing {
+		h ^= zobristPass
+	}
+	return
+}
@@ -0,0 +1,54 @@
+package kalah
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Move represents the pit of the player to sow, counted from 0
+// in the order of sowing, or a pass.
+type Move int8
+
+// Pass is the move of a player who must pass.
+const Pass Move = -1
+
+var invalidMove Move = -2
+
+// String converts a Move to the string representation,
+// which is the number of the pit counted from 1, or "pass".
+func (m Move) String() string {
+	switch {
+	case m == Pass:
+		return "pass"
+	case m < 0:
+		return "(none)"
+	}
+	return strconv.Itoa(int(m) + 1)
+}
+
+// ParseMove converts the string representation of a pit back
+// to a Move.  It does not tell if the move is allowed.
+func ParseMove(str string) (Move, error) {
+	k, err := strconv.Atoi(str)
+	if err != nil || k < 1 || k > MaxPits {
+		return invalidMove, errors.New("kalah: bad move " + str)
+	}
+	return Move(k - 1), nil
+}
+
+// moveKey converts a Move to the move of Play.
+func (s *State) moveKey(m Move) int {
+	if m == Pass {
+		return s.Pits
+	}
+	return int(m)
+}
+
+// MoveKey returns the pit of the last move, or Pits for a pass.
+// It is 0 at the start, where there is no last move.
+func (s *State) MoveKey() int {
+	if s.LastMove == invalidMove {
+		return 0
+	}
+	return s.moveKey(s.LastMove)
+}
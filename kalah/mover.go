@@ -0,0 +1,93 @@
+package kalah
+
+import "github.com/z-rui/game"
+
+// undo is what Undo needs to take back a move.
+type undo struct {
+	board    [2*MaxPits + 2]uint8
+	lastMove Move
+	passing  bool
+}
+
+// Moves appends the moves allowed in the current state to moves,
+// in the same order as Next.  A move is the pit to sow, starting
+// from the one nearest to the store, or Pits for a pass.
+func (s *State) Moves(moves []int) []int {
+	switch {
+	case s.IsEnd():
+		return moves
+	case s.passing:
+		return append(moves, s.Pits)
+	}
+	for k := s.Pits - 1; k >= 0; k-- {
+		if s.Board[s.pit(s.Turn, k)] != 0 {
+			moves = append(moves, k)
+		}
+	}
+	return moves
+}
+
+// Play makes the move in place.
+func (s *State) Play(move int) {
+	u := undo{lastMove: s.LastMove, passing: s.passing}
+	copy(u.board[:], s.Board)
+	s.history = append(s.history, u)
+	if move == s.Pits {
+		s.LastMove = Pass
+		s.passing = false
+	} else {
+		s.LastMove = Move(move)
+		s.passing = s.sow(move) && !s.IsEnd()
+	}
+	s.Turn ^= O ^ X
+}
+
+// sow sows the seeds of pit k of the player to move, and tells
+// if the player moves again.
+func (s *State) sow(k int) (again bool) {
+	n := len(s.Board)
+	i := s.pit(s.Turn, k)
+	store, other := s.pit(s.Turn, s.Pits), s.pit(s.Turn^O^X, s.Pits)
+	seeds := s.Board[i]
+	s.Board[i] = 0
+	for ; seeds > 0; seeds-- {
+		if i = (i + 1) % n; i == other {
+			i = (i + 1) % n
+		}
+		s.Board[i]++
+	}
+	switch opposite := 2*s.Pits - i; {
+	case i == store:
+		again = true
+	case s.Board[i] == 1 && store-s.Pits <= i && i < store && s.Board[opposite] != 0:
+		s.Board[store] += 1 + s.Board[opposite]
+		s.Board[i], s.Board[opposite] = 0, 0
+	}
+	if s.InRow(O) == 0 || s.InRow(X) == 0 {
+		// the game ends, and the seeds left go to the stores
+		for _, p := range [2]Player{O, X} {
+			for k := 0; k < s.Pits; k++ {
+				s.Board[s.pit(p, s.Pits)] += s.Board[s.pit(p, k)]
+				s.Board[s.pit(p, k)] = 0
+			}
+		}
+		again = false
+	}
+	return
+}
+
+// Undo takes back the last move played.
+func (s *State) Undo() {
+	n := len(s.history) - 1
+	u := &s.history[n]
+	copy(s.Board, u.board[:])
+	s.LastMove = u.lastMove
+	s.passing = u.passing
+	s.history = s.history[:n]
+	s.Turn ^= O ^ X
+}
+
+// Clone returns a copy of the current state.
+func (s *State) Clone() game.Mover {
+	return s.clone()
+}
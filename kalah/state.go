@@ -0,0 +1,224 @@
+// Package kalah implements Kalah, a game of the Mancala family.
+// Each player has a row of pits holding seeds, and a store at the
+// right end of the row.  A move takes all the seeds of a pit of the
+// player and sows them one by one counterclockwise, into the following
+// pits and the player's own store, but not the other player's store.
+//
+// If the last seed lands in the player's store, the player moves again.
+// If it lands in an empty pit of the player, and the pit opposite has
+// seeds, both the last seed and those opposite are captured into the
+// player's store.  The game ends when either row is empty, and each
+// player then collects the seeds left in their row.  The player with
+// more seeds in the store wins.  The game also ends as soon as a
+// player has more than half of the seeds in the store.
+//
+// The search in package game assumes that the players take turns,
+// so a player moving again is modelled as the other player passing,
+// like a pass in Othello: after the move, the other player is to move
+// but must pass.
+package kalah
+
+import (
+	"errors"
+	"fmt"
+	"github.com/z-rui/game"
+	"strings"
+)
+
+// Player represents a player.  O, who moves first, has the lower row,
+// and X the upper row.
+type Player uint8
+
+const (
+	O Player = 1 + iota
+	X
+)
+
+// String converts a player to the string representation.
+func (p Player) String() string {
+	switch p {
+	case O:
+		return "O"
+	case X:
+		return "X"
+	default:
+		return " "
+	}
+}
+
+// Limits of the game.
+const (
+	MaxPits  = 10
+	MaxSeeds = 12
+)
+
+// Game describes a game of Kalah.
+type Game struct {
+	Pits  int // number of pits of each player
+	Seeds int // number of seeds in each pit at the start
+}
+
+// Standard is the usual game, Kalah(6, 4).
+var Standard = Game{Pits: 6, Seeds: 4}
+
+// State represents the current state of the game.
+type State struct {
+	Game
+	// Board holds the number of seeds in O's pits, O's store,
+	// X's pits and X's store, in the order of sowing.
+	Board    []uint8
+	LastMove Move
+	Turn     Player // must be O or X
+	passing  bool   // the player to move must pass
+	history  []undo // to take back the moves played by Play
+}
+
+// NewState returns a new state at the start of the game.
+func (g Game) NewState() (*State, error) {
+	if g.Pits < 1 || g.Pits > MaxPits {
+		return nil, errors.New("kalah: bad number of pits")
+	}
+	if g.Seeds < 1 || g.Seeds > MaxSeeds {
+		return nil, errors.New("kalah: bad number of seeds")
+	}
+	s := &State{
+		Game:     g,
+		Board:    make([]uint8, 2*g.Pits+2),
+		LastMove: invalidMove,
+		Turn:     O,
+	}
+	for k := 0; k < g.Pits; k++ {
+		s.Board[k] = uint8(g.Seeds)
+		s.Board[g.Pits+1+k] = uint8(g.Seeds)
+	}
+	return s, nil
+}
+
+// pit returns the index in the board of the player's pit k,
+// counted from 0; pit Pits is the store.
+func (s *State) pit(p Player, k int) int {
+	if p == X {
+		return s.Pits + 1 + k
+	}
+	return k
+}
+
+// Store returns the number of seeds in the player's store.
+func (s *State) Store(p Player) int {
+	return int(s.Board[s.pit(p, s.Pits)])
+}
+
+// InRow returns the number of seeds in the player's pits.
+func (s *State) InRow(p Player) (n int) {
+	for k := 0; k < s.Pits; k++ {
+		n += int(s.Board[s.pit(p, k)])
+	}
+	return
+}
+
+// MustPass tells if the player to move must pass, because the
+// other player moves again.
+func (s *State) MustPass() bool {
+	return s.passing
+}
+
+// IsEnd tells if the game has ended, or is decided by a player
+// having more than half of the seeds in the store.
+func (s *State) IsEnd() bool {
+	half := s.Pits * s.Seeds
+	if s.Store(O) > half || s.Store(X) > half {
+		return true
+	}
+	return s.InRow(O) == 0 && s.InRow(X) == 0
+}
+
+// storeWeight is what a seed in the store is worth in the evaluation,
+// against a seed in a pit.
+const storeWeight = 4
+
+// Eval returns the evaluation of the current state.
+// A player with more than half of the seeds in the store has won.
+// Otherwise the state is evaluated by the seeds in the stores,
+// and less so by those in the pits.
+func (s *State) Eval() (eval game.Evaluation) {
+	o, x := s.Store(O), s.Store(X)
+	half := s.Pits * s.Seeds
+	switch {
+	case o > half:
+		return game.Won
+	case x > half:
+		return game.Lost
+	case s.IsEnd():
+		return 0
+	}
+	return game.Evaluation(storeWeight*(o-x) + s.InRow(O) - s.InRow(X))
+}
+
+// Next returns all possible next states.
+func (s *State) Next() (nxt []game.State) {
+	for _, k := range s.Moves(nil) {
+		t := s.clone()
+		t.Play(k)
+		t.history = nil
+		nxt = append(nxt, t)
+	}
+	return
+}
+
+// Move returns the next state based on the move, which may be
+// a pass.  It returns nil if the move is not allowed.
+func (s *State) Move(m Move) *State {
+	switch {
+	case s.IsEnd():
+		return nil
+	case m == Pass:
+		if !s.passing {
+			return nil
+		}
+	case s.passing || m < 0 || int(m) >= s.Pits || s.Board[s.pit(s.Turn, int(m))] == 0:
+		return nil
+	}
+	t := s.clone()
+	t.Play(t.moveKey(m))
+	t.history = nil
+	return t
+}
+
+// Pass returns a new state after a player passes.
+func (s *State) Pass() *State {
+	return s.Move(Pass)
+}
+
+// clone returns a copy of s without the history.
+func (s *State) clone() *State {
+	t := *s
+	t.Board = append([]uint8(nil), s.Board...)
+	t.history = nil
+	return &t
+}
+
+// String draws the board, with X's row and store above and on the
+// left, and O's row and store below and on the right.  The pits are
+// numbered in the order of sowing of each player.
+func (s *State) String() string {
+	var b strings.Builder
+	p := s.Pits
+	b.WriteString(" X ")
+	for k := p; k >= 1; k-- {
+		fmt.Fprintf(&b, "%3d ", k)
+	}
+	b.WriteString("\n   ")
+	for k := p - 1; k >= 0; k-- {
+		fmt.Fprintf(&b, "[%2d]", s.Board[s.pit(X, k)])
+	}
+	fmt.Fprintf(&b, "\n%2d %s %2d\n   ", s.Store(X), strings.Repeat(" ", 4*p), s.Store(O))
+	for k := 0; k < p; k++ {
+		fmt.Fprintf(&b, "[%2d]", s.Board[s.pit(O, k)])
+	}
+	b.WriteString("\n O ")
+	for k := 1; k <= p; k++ {
+		fmt.Fprintf(&b, "%3d ", k)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,235 @@
+package kalah
+
+import (
+	"context"
+	"github.com/z-rui/game"
+	"math/rand"
+	"testing"
+)
+
+// play plays the moves from the start of g.
+func play(t *testing.T, g Game, moves ...string) *State {
+	s, err := g.NewState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, str := range moves {
+		var t2 *State
+		if str == "pass" {
+			t2 = s.Pass()
+		} else {
+			m, err := ParseMove(str)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t2 = s.Move(m)
+		}
+		if t2 == nil {
+			t.Fatalf("move %v not allowed", str)
+		}
+		s = t2
+	}
+	return s
+}
+
+// board returns a state with O to move and the pits of O and X
+// as given; the stores take the seeds left to make up the game.
+func board(t *testing.T, g Game, o, x []uint8) *State {
+	s := play(t, g)
+	copy(s.Board, o)
+	copy(s.Board[g.Pits+1:], x)
+	s.Board[g.Pits], s.Board[2*g.Pits+1] = 0, 0
+	left := 2 * g.Pits * g.Seeds
+	for _, n := range s.Board {
+		left -= int(n)
+	}
+	s.Board[g.Pits] = uint8(left / 2)
+	s.Board[2*g.Pits+1] = uint8(left - left/2)
+	return s
+}
+
+func TestPerft(t *testing.T) {
+	// O's pit 3 reaches the store at once, and X passes
+	for depth, want := range []uint64{1, 6, 31, 136, 623, 2630, 11662, 50881} {
+		s := play(t, Standard)
+		if got := game.Perft(s, uint(depth)); got != want {
+			t.Errorf("depth %d: got %d, want %d", depth, got, want)
+		}
+		if got := game.Perft(struct{ game.State }{s}, uint(depth)); got != want {
+			t.Errorf("depth %d without mover: got %d, want %d", depth, got, want)
+		}
+	}
+	if k := play(t, Standard).MoveKey(); k < 0 {
+		t.Errorf("negative move key %d at the start", k)
+	}
+}
+
+func TestExtraTurn(t *testing.T) {
+	// the fourth seed of pit 3 lands in the store
+	s := play(t, Standard, "3")
+	if !s.MustPass() || s.Turn != X || s.Store(O) != 1 {
+		t.Fatalf("no extra turn: %v", s)
+	}
+	if s.Move(0) != nil {
+		t.Errorf("X moves instead of passing")
+	}
+	nxt := s.Next()
+	if len(nxt) != 1 {
+		t.Fatalf("got %d next states, want a pass", len(nxt))
+	}
+	u := nxt[0].(*State)
+	if u.MustPass() || u.Turn != O || u.LastMove != Pass {
+		t.Errorf("pass does not give O the turn")
+	}
+	if u.Pass() != nil {
+		t.Errorf("O passes")
+	}
+	if s := play(t, Standard, "3", "pass", "6"); s.MustPass() || s.Turn != X {
+		t.Errorf("extra turn without reaching the store")
+	}
+}
+
+func TestSow(t *testing.T) {
+	// 8 seeds from O's pit 6 go round X's row, past X's store,
+	// and the last lands in O's pit 1
+	s := board(t, Standard, []uint8{2, 0, 0, 0, 0, 8}, []uint8{1, 2, 3, 4, 5, 6})
+	u := s.Move(5)
+	if u.Store(X) != s.Store(X) || u.Store(O) != s.Store(O)+1 {
+		t.Errorf("wrong stores after sowing past X's store:\n%v", u)
+	}
+	for k := 0; k < s.Pits; k++ {
+		if u.Board[u.pit(X, k)] != s.Board[s.pit(X, k)]+1 {
+			t.Errorf("X's pit %d not sowed:\n%v", k+1, u)
+		}
+	}
+	if u.Board[0] != 3 || u.Board[5] != 0 || u.MustPass() {
+		t.Errorf("wrong last pit:\n%v", u)
+	}
+}
+
+func TestCapture(t *testing.T) {
+	s := board(t, Standard, []uint8{1, 0, 0, 0, 0, 1}, []uint8{1, 2, 3, 4, 5, 6})
+	store := s.Store(O)
+	// pit 1 sows into the empty pit 2, opposite to X's pit 5
+	u := s.Move(0)
+	if u.Store(O) != store+6 || u.Board[1] != 0 || u.Board[s.pit(X, 4)] != 0 {
+		t.Errorf("no capture:\n%v", u)
+	}
+	if u.Eval() <= s.Eval() {
+		t.Errorf("capture not better: %v <= %v", u.Eval(), s.Eval())
+	}
+	// no capture when the pit opposite is empty
+	s.Board[s.pit(X, 4)] = 0
+	s.Board[s.pit(X, 5)] += 5
+	if u := s.Move(0); u.Store(O) != store || u.Board[1] != 1 {
+		t.Errorf("capture from an empty pit:\n%v", u)
+	}
+	// no capture in X's empty pit
+	s = board(t, Standard, []uint8{1, 0, 0, 0, 0, 2}, []uint8{0, 3, 3, 3, 3, 3})
+	if u := s.Move(5); u.Store(O) != s.Store(O)+1 || u.Board[u.pit(X, 0)] != 1 {
+		t.Errorf("capture in X's pit:\n%v", u)
+	}
+	// 13 seeds go round the board and land in the pit they left,
+	// which is empty, capturing X's pit 6
+	s = board(t, Standard, []uint8{13, 1, 1, 1, 1, 1}, []uint8{1, 1, 1, 1, 1, 1})
+	store = s.Store(O)
+	u = s.Move(0)
+	if u.Store(O) != store+1+1+2 || u.Board[0] != 0 || u.Board[u.pit(X, 5)] != 0 {
+		t.Errorf("no capture after sowing around:\n%v", u)
+	}
+}
+
+func TestEnd(t *testing.T) {
+	// O's row runs out, and X's seeds left go to X's store
+	s := board(t, Standard, []uint8{0, 0, 0, 0, 0, 1}, []uint8{1, 2, 0, 0, 0, 0})
+	u := s.Move(5)
+	if !u.IsEnd() || u.MustPass() || len(u.Next()) != 0 {
+		t.Fatalf("game goes on:\n%v", u)
+	}
+	if u.Store(X) != s.Store(X)+3 || u.InRow(X) != 0 || u.Store(O) != s.Store(O)+1 {
+		t.Errorf("seeds not collected:\n%v", u)
+	}
+	want := game.Evaluation(0)
+	switch o, x := u.Store(O), u.Store(X); {
+	case o > x:
+		want = game.Won
+	case o < x:
+		want = game.Lost
+	}
+	if u.Eval() != want {
+		t.Errorf("got %v, want %v", u.Eval(), want)
+	}
+	// O captures X's last seeds, and O's seeds left go to O's store
+	s = board(t, Standard, []uint8{1, 0, 2, 3, 0, 0}, []uint8{0, 0, 0, 0, 4, 0})
+	u = s.Move(0)
+	if !u.IsEnd() || u.InRow(O) != 0 || u.Store(O) != s.Store(O)+1+4+2+3 || u.Store(X) != s.Store(X) {
+		t.Errorf("seeds not collected after the capture:\n%v", u)
+	}
+	// more than half of the seeds decides the game
+	s = board(t, Standard, []uint8{1, 0, 0, 0, 0, 0}, []uint8{1, 0, 0, 0, 0, 0})
+	s.Board[Standard.Pits], s.Board[2*Standard.Pits+1] = 25, 21
+	if !s.IsEnd() || s.Eval() != game.Won {
+		t.Errorf("got %v, want %v", s.Eval(), game.Won)
+	}
+	if len(s.Moves(nil)) != 0 || len(s.Next()) != 0 || s.Move(0) != nil {
+		t.Errorf("decided game goes on")
+	}
+	// the seed deciding the game does not give another turn
+	s = board(t, Standard, []uint8{0, 0, 0, 0, 0, 1}, []uint8{1, 0, 0, 0, 0, 0})
+	s.Board[Standard.Pits], s.Board[2*Standard.Pits+1] = 24, 22
+	if u := s.Move(5); !u.IsEnd() || u.MustPass() || u.Eval() != game.Won {
+		t.Errorf("decided by the last seed in the store:\n%v", u)
+	}
+}
+
+func TestDecided(t *testing.T) {
+	// the search stops at a decided game, as MinMax does
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 20; n++ {
+		s := play(t, Standard)
+		for !s.IsEnd() && s.Store(O) < 20 && s.Store(X) < 20 {
+			nxt := s.Next()
+			s = nxt[r.Intn(len(nxt))].(*State)
+		}
+		for depth := uint(1); depth <= 6; depth++ {
+			_, want := game.MinMax(s, depth, false)
+			res, err := game.Search(context.Background(), s, false,
+				game.Options{MaxDepth: depth, Table: game.NewTable(16), PVS: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Eval != want {
+				t.Errorf("depth %d: got %v, MinMax got %v\n%v", depth, res.Eval, want, s)
+			}
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	// the search through passes agrees with MinMax by Next
+	s := play(t, Game{Pits: 3, Seeds: 2})
+	_, want := game.MinMax(s, 40, false)
+	r, _ := game.SearchOf(context.Background(), s, false,
+		game.Options{MaxDepth: 40, Table: game.NewTable(16), PVS: true, Killers: true, History: true})
+	if r.Eval != want || !r.Eval.IsWin() && !r.Eval.IsLoss() && r.Eval != 0 {
+		t.Errorf("got %v, want %v", r.Eval, want)
+	}
+	// O takes the extra turn by pit 6 before the capture by pit 1
+	s = board(t, Standard, []uint8{4, 0, 0, 0, 0, 1}, []uint8{0, 0, 6, 0, 0, 1})
+	r, _ = game.SearchOf(context.Background(), s, false,
+		game.Options{MaxDepth: 4, Table: game.NewTable(16)})
+	if r.Next == nil || r.Next.LastMove != 5 || len(r.PV) < 3 || r.PV[2].LastMove != 0 {
+		t.Errorf("got PV %v, want 6 pass 1", r.PV)
+	}
+}
+
+func TestParseMove(t *testing.T) {
+	for _, str := range []string{"0", "11", "x", ""} {
+		if _, err := ParseMove(str); err == nil {
+			t.Errorf("%q parsed", str)
+		}
+	}
+	if m, err := ParseMove("6"); err != nil || m != 5 || m.String() != "6" {
+		t.Errorf("got %v, %v", m, err)
+	}
+}
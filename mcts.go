@@ -0,0 +1,235 @@
+package game
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// MCTSScale is the evaluation reported by MCTS for a next state
+// through which every playout was won.
+// One through which every playout was lost is reported as -MCTSScale.
+const MCTSScale = 1000
+
+// MCTS is a Monte Carlo tree search engine using the UCT formula.
+// Instead of evaluating states, it plays many random games
+// (playouts) to the end and prefers the moves that win most often.
+// Only the evaluation of a state at the end of the game is used,
+// and only its sign matters.
+//
+// An MCTS keeps its tree between searches, so when the next search
+// starts from a state reached from the previous one, the playouts
+// done before are reused.  The states must implement Hasher
+// for this to work.
+type MCTS struct {
+	// Exploration is the exploration constant in the UCT formula.
+	// Larger values make the search wider.  Zero means √2.
+	Exploration float64
+	// Playout chooses a next state during a playout.
+	// If nil, it is chosen uniformly at random.
+	Playout func(nxt []State, r *rand.Rand) State
+	// Iterations limits the number of playouts per search.
+	// Zero means no limit.
+	Iterations int
+	// Budget is the wall-clock time a search may take.
+	// Zero means no limit.
+	Budget time.Duration
+	// Rand is the source of randomness.
+	// If nil, one seeded by the current time is used.
+	Rand *rand.Rand
+
+	root *node
+}
+
+// node is a node of the search tree.
+type node struct {
+	state    State
+	parent   *node
+	children []*node
+	untried  []State // next states not yet in children
+	visits   float64
+	score    float64 // total outcome, for the player moving into the node
+	findMin  bool    // whether the player to move minimises
+}
+
+func newNode(s State, parent *node, findMin bool) *node {
+	return &node{
+		state:   s,
+		parent:  parent,
+		untried: s.Next(),
+		findMin: findMin,
+	}
+}
+
+// mctsCheckInterval is the number of playouts between two
+// checks of the deadline and the cancellation.
+const mctsCheckInterval = 64
+
+// Search finds the best next state of s by playouts, until the
+// number of iterations is reached, the budget is used up or ctx
+// is done.  At least one of them must be set.
+// If the search is stopped through ctx, it returns ctx.Err()
+// together with the best move found so far.
+//
+// In the result, Eval is the mean outcome of the playouts through
+// Next, from -MCTSScale (all lost) to MCTSScale (all won);
+// Nodes is the number of playouts; Depth is the length of PV.
+func (m *MCTS) Search(ctx context.Context, s State, findMin bool) (r *Result, err error) {
+	start := time.Now()
+	if m.Rand == nil {
+		m.Rand = rand.New(rand.NewSource(start.UnixNano()))
+	}
+	root := m.reuse(s, findMin)
+	if root == nil {
+		root = newNode(s, nil, findMin)
+	}
+	m.root = root
+
+	r = new(Result)
+	var n int
+	terminal := len(root.untried) == 0 && len(root.children) == 0
+	for !terminal && (m.Iterations == 0 || n < m.Iterations) {
+		if n%mctsCheckInterval == 0 {
+			if err = ctx.Err(); err != nil {
+				break
+			}
+			if m.Budget > 0 && time.Since(start) > m.Budget {
+				break
+			}
+		}
+		m.iterate(root)
+		n++
+	}
+	r.Nodes = uint64(n)
+	r.Elapsed = time.Since(start)
+
+	for t := root; ; {
+		c := t.mostVisited()
+		if c == nil {
+			break
+		}
+		r.PV = append(r.PV, c.state)
+		t = c
+	}
+	r.Depth = uint(len(r.PV))
+	if c := root.mostVisited(); c != nil {
+		r.Next = c.state
+		mean := c.score / c.visits // from the view of the root player
+		if findMin {
+			mean = 1 - mean
+		}
+		r.Eval = Evaluation(math.Round((2*mean - 1) * MCTSScale))
+	}
+	return
+}
+
+// reuse finds s in the tree kept from the previous search,
+// among the root and its descendants of up to two plies.
+func (m *MCTS) reuse(s State, findMin bool) *node {
+	h, ok := s.(Hasher)
+	if !ok || m.root == nil {
+		return nil
+	}
+	hash := h.Hash()
+	same := func(t *node) bool {
+		th, ok := t.state.(Hasher)
+		return ok && t.findMin == findMin && th.Hash() == hash
+	}
+	found := func(t *node) *node {
+		t.parent = nil
+		return t
+	}
+	if same(m.root) {
+		return found(m.root)
+	}
+	for _, c := range m.root.children {
+		if same(c) {
+			return found(c)
+		}
+		for _, g := range c.children {
+			if same(g) {
+				return found(g)
+			}
+		}
+	}
+	return nil
+}
+
+// iterate runs one iteration: selection, expansion, playout
+// and backpropagation.
+func (m *MCTS) iterate(t *node) {
+	for len(t.untried) == 0 && len(t.children) > 0 {
+		t = t.selectChild(m.exploration())
+	}
+	if k := len(t.untried); k > 0 {
+		i := m.Rand.Intn(k)
+		s := t.untried[i]
+		t.untried[i] = t.untried[k-1]
+		t.untried = t.untried[:k-1]
+		c := newNode(s, t, !t.findMin)
+		t.children = append(t.children, c)
+		t = c
+	}
+	outcome := m.playout(t.state, t.untried)
+	for ; t != nil; t = t.parent {
+		t.visits++
+		if t.parent != nil && t.parent.findMin {
+			t.score += 1 - outcome
+		} else {
+			t.score += outcome
+		}
+	}
+}
+
+func (m *MCTS) exploration() float64 {
+	if m.Exploration == 0 {
+		return math.Sqrt2
+	}
+	return m.Exploration
+}
+
+// playout plays randomly from s to the end of the game,
+// and returns the outcome for the maximising player:
+// 1 for a win, 0 for a loss and 0.5 for a draw.
+func (m *MCTS) playout(s State, nxt []State) float64 {
+	for len(nxt) > 0 {
+		if m.Playout != nil {
+			s = m.Playout(nxt, m.Rand)
+		} else {
+			s = nxt[m.Rand.Intn(len(nxt))]
+		}
+		nxt = s.Next()
+	}
+	switch eval := s.Eval(); {
+	case eval > 0:
+		return 1
+	case eval < 0:
+		return 0
+	}
+	return 0.5
+}
+
+// selectChild returns the child with the highest UCT value.
+func (t *node) selectChild(c float64) (best *node) {
+	logN := math.Log(t.visits)
+	var bestValue float64
+	for _, u := range t.children {
+		value := u.score/u.visits + c*math.Sqrt(logN/u.visits)
+		if best == nil || value > bestValue {
+			best, bestValue = u, value
+		}
+	}
+	return
+}
+
+// mostVisited returns the child visited most often,
+// or nil if there is no child.
+func (t *node) mostVisited() (best *node) {
+	for _, u := range t.children {
+		if best == nil || u.visits > best.visits {
+			best = u
+		}
+	}
+	return
+}
@@ -0,0 +1,60 @@
+package mnk
+
+import "github.com/z-rui/game"
+
+// Moves appends the moves allowed in the current state to moves,
+// in the same order as Next.  A move is the index of the cell, i*n+j
+// for n columns.
+func (s *State) Moves(moves []int) []int {
+	if s.IsEnd() {
+		return moves
+	}
+	if s.Radius > 0 && !s.LastMove.Valid() {
+		return append(moves, s.center())
+	}
+	for k, c := range s.Board {
+		if c == Empty && (s.Radius == 0 || s.near(k/s.N, k%s.N)) {
+			moves = append(moves, k)
+		}
+	}
+	return moves
+}
+
+// center returns the cell of the first move when Radius is set.
+func (s *State) center() int {
+	return s.M/2*s.N + s.N/2
+}
+
+// near tells if a piece is within Radius from (i, j).
+func (s *State) near(i, j int) bool {
+	for di := -s.Radius; di <= s.Radius; di++ {
+		for dj := -s.Radius; dj <= s.Radius; dj++ {
+			if s.cell(i+di, j+dj) != Empty {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Play makes the move in place.
+func (s *State) Play(move int) {
+	s.history = append(s.history, s.LastMove)
+	s.Board[move] = s.Turn
+	s.LastMove = Move{move / s.N, move % s.N}
+	s.Turn ^= O ^ X
+}
+
+// Undo takes back the last move played.
+func (s *State) Undo() {
+	s.Board[s.LastMove.I*s.N+s.LastMove.J] = Empty
+	n := len(s.history) - 1
+	s.LastMove = s.history[n]
+	s.history = s.history[:n]
+	s.Turn ^= O ^ X
+}
+
+// Clone returns a copy of the current state.
+func (s *State) Clone() game.Mover {
+	return s.clone()
+}
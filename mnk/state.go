@@ -0,0 +1,242 @@
+// Package mnk implements m,n,k-games: two players take turns to place
+// their pieces on an m×n board, and the first to get k in a row,
+// horizontally, vertically or diagonally, wins.
+// Tic-Tac-Toe is the 3,3,3-game, and Gomoku (free-style) the 15,15,5-game.
+package mnk
+
+import (
+	"errors"
+	"github.com/z-rui/game"
+)
+
+// Cell represents a cell of the board.
+// It has three states: Empty, O and X.
+type Cell uint8
+
+const (
+	Empty Cell = iota
+	O
+	X
+)
+
+// String converts a cell to the string representation.
+func (c Cell) String() string {
+	switch c {
+	case O:
+		return "O"
+	case X:
+		return "X"
+	default:
+		return " "
+	}
+}
+
+// MaxSize is the largest number of rows or columns of the board.
+const MaxSize = 26
+
+// Game describes an m,n,k-game.
+type Game struct {
+	M, N, K int // rows, columns, and pieces in a row to win
+	// Radius limits the moves of Next to the empty cells within this
+	// distance (in rows and columns) from a piece on the board, which
+	// makes large boards searchable.  The first move, by Move as well,
+	// must then be at the center.
+	// Zero means any empty cell.
+	Radius int
+}
+
+// Well-known games.
+var (
+	TicTacToe = Game{M: 3, N: 3, K: 3}
+	Gomoku    = Game{M: 15, N: 15, K: 5, Radius: 2}
+)
+
+// rules is what the states of a game share.
+type rules struct {
+	Game
+	weights []game.Evaluation // of a line by the pieces in it
+}
+
+// State represents the current state of the game.
+type State struct {
+	*rules
+	Board    []Cell // cells in row-major order
+	LastMove Move
+	Turn     Cell   // must be O or X
+	history  []Move // last moves before those played by Play
+}
+
+// NewState returns a new state at the start of the game.
+func (g Game) NewState() (*State, error) {
+	if g.M < 1 || g.N < 1 || g.M > MaxSize || g.N > MaxSize {
+		return nil, errors.New("mnk: bad board size")
+	}
+	if g.K < 1 || g.K > g.M && g.K > g.N {
+		return nil, errors.New("mnk: bad number in a row")
+	}
+	if g.Radius < 0 {
+		return nil, errors.New("mnk: bad radius")
+	}
+	s := &State{
+		rules:    newRules(g),
+		Board:    make([]Cell, g.M*g.N),
+		LastMove: invalidMove,
+		Turn:     O,
+	}
+	return s, nil
+}
+
+// newRules prepares the rules of the game.
+func newRules(g Game) *rules {
+	r := &rules{Game: g}
+	// a line of c pieces that can still be completed weighs 4^c,
+	// with a cap to keep the sum away from the win or loss
+	r.weights = make([]game.Evaluation, g.K+1)
+	w := game.Evaluation(1)
+	for c := 1; c <= g.K; c++ {
+		if w < 1<<16 {
+			w *= 4
+		}
+		r.weights[c] = w
+	}
+	return r
+}
+
+// Dim returns the dimension of the board
+func (s *State) Dim() (int, int) {
+	return s.M, s.N
+}
+
+// Get returns the string representation at (i, j)
+func (s *State) Get(i, j int) string {
+	return s.Board[i*s.N+j].String()
+}
+
+// cell returns the cell at (i, j), or Empty if it is off the board.
+func (s *State) cell(i, j int) Cell {
+	if i < 0 || i >= s.M || j < 0 || j >= s.N {
+		return Empty
+	}
+	return s.Board[i*s.N+j]
+}
+
+// directions are the four directions of lines.
+var directions = [4][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}}
+
+// Won tells if the last move made k in a row.
+func (s *State) Won() bool {
+	if !s.LastMove.Valid() {
+		return false
+	}
+	i, j := s.LastMove.I, s.LastMove.J
+	c := s.cell(i, j)
+	for _, d := range directions {
+		n := 1
+		for k := 1; s.cell(i+k*d[0], j+k*d[1]) == c; k++ {
+			n++
+		}
+		for k := 1; s.cell(i-k*d[0], j-k*d[1]) == c; k++ {
+			n++
+		}
+		if n >= s.K {
+			return true
+		}
+	}
+	return false
+}
+
+// full tells if no cell is empty.
+func (s *State) full() bool {
+	for _, c := range s.Board {
+		if c == Empty {
+			return false
+		}
+	}
+	return true
+}
+
+// IsEnd tells if the game has ended.
+func (s *State) IsEnd() bool {
+	return s.Won() || s.full()
+}
+
+// Eval returns the evaluation of the current state.
+// A state not ended is evaluated by the lines of k cells
+// that either player can still complete: each line holding
+// c pieces of a player counts 4^c for that player.
+func (s *State) Eval() (eval game.Evaluation) {
+	if s.Won() {
+		if s.Turn == O {
+			return game.Lost
+		}
+		return game.Won
+	}
+	for i := 0; i < s.M; i++ {
+		for j := 0; j < s.N; j++ {
+			for _, d := range directions {
+				eval += s.line(i, j, d[0], d[1])
+			}
+		}
+	}
+	return
+}
+
+// line evaluates the line of k cells starting from (i, j)
+// in the direction (di, dj).
+func (s *State) line(i, j, di, dj int) game.Evaluation {
+	ei, ej := i+(s.K-1)*di, j+(s.K-1)*dj
+	if ei < 0 || ei >= s.M || ej < 0 || ej >= s.N {
+		return 0
+	}
+	var o, x int
+	for k := 0; k < s.K; k++ {
+		switch s.Board[(i+k*di)*s.N+j+k*dj] {
+		case O:
+			o++
+		case X:
+			x++
+		}
+	}
+	switch {
+	case x == 0:
+		return s.weights[o]
+	case o == 0:
+		return -s.weights[x]
+	}
+	return 0
+}
+
+// Next returns all possible next states.
+func (s *State) Next() (nxt []game.State) {
+	for _, k := range s.Moves(nil) {
+		t := s.clone()
+		t.Play(k)
+		t.history = nil
+		nxt = append(nxt, t)
+	}
+	return
+}
+
+// Move returns the next state based on the move.
+// It returns nil if the move is not allowed.
+func (s *State) Move(m Move) *State {
+	if !m.Valid() || m.I >= s.M || m.J >= s.N || s.IsEnd() {
+		return nil
+	}
+	k := m.I*s.N + m.J
+	if s.Board[k] != Empty || s.Radius > 0 && !s.LastMove.Valid() && k != s.center() {
+		return nil
+	}
+	t := s.clone()
+	t.Play(k)
+	t.history = nil
+	return t
+}
+
+// clone returns a copy of s without the history.
+func (s *State) clone() *State {
+	t := *s
+	t.Board = append([]Cell(nil), s.Board...)
+	t.history = nil
+	return &t
+}
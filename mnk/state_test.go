@@ -0,0 +1,135 @@
+package mnk
+
+import (
+	"context"
+	"github.com/z-rui/game"
+	"testing"
+)
+
+// play plays the moves from the start of g.
+func play(t *testing.T, g Game, moves ...string) *State {
+	s, err := g.NewState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, str := range moves {
+		m, err := ParseMove(str)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s = s.Move(m); s == nil {
+			t.Fatalf("move %v not allowed", m)
+		}
+	}
+	return s
+}
+
+func TestTicTacToe(t *testing.T) {
+	s := play(t, TicTacToe)
+	for depth, want := range []uint64{
+		1, 9, 72, 504, 3024, 15120, 54720, 148176, 200448, 127872,
+	} {
+		if got := game.Perft(s, uint(depth)); got != want {
+			t.Errorf("depth %d: got %d, want %d", depth, got, want)
+		}
+	}
+	if _, eval := game.MinMax(s, 9, false); eval != 0 {
+		t.Errorf("initial position: got %v, want a draw", eval)
+	}
+	if k := s.MoveKey(); k != 0 {
+		t.Errorf("initial position: got move key %d, want 0", k)
+	}
+	if k := play(t, TicTacToe, "C2").MoveKey(); k != 2*3+1 {
+		t.Errorf("C2: got move key %d, want %d", k, 2*3+1)
+	}
+}
+
+func TestWon(t *testing.T) {
+	// any first move, unlike Gomoku
+	free := Game{M: 15, N: 15, K: 5}
+	for _, moves := range [][]string{
+		{"H8", "A1", "H9", "A2", "H10", "A3", "H11", "A4", "H12"},   // row
+		{"C3", "A1", "D3", "A2", "E3", "A3", "F3", "A4", "G3"},      // column
+		{"C3", "A1", "D4", "A2", "E5", "A3", "G7", "A4", "F6"},      // diagonal
+		{"C7", "A1", "D6", "A2", "E5", "A3", "F4", "A4", "G3"},      // anti-diagonal
+		{"O15", "A1", "N14", "A2", "M13", "A3", "L12", "A4", "K11"}, // corner
+	} {
+		s := play(t, free, moves...)
+		if !s.Won() || !s.IsEnd() || s.Eval() != game.Won {
+			t.Errorf("%v: O has not won", moves)
+		}
+		if len(s.Next()) != 0 {
+			t.Errorf("%v: game goes on after a win", moves)
+		}
+		s = play(t, free, moves[:len(moves)-1]...)
+		if s.Won() || s.IsEnd() {
+			t.Errorf("%v: won with four", moves)
+		}
+	}
+}
+
+func TestEval(t *testing.T) {
+	if e := play(t, Gomoku, "H8", "A1").Eval(); e <= 0 {
+		// O in the center has more lines than X in the corner
+		t.Errorf("got %v, want positive", e)
+	}
+	open, blocked := play(t, Gomoku, "H8", "A1", "H9", "A2", "H10"),
+		play(t, Gomoku, "H8", "H7", "H9", "A2", "H10")
+	if open.Eval() <= blocked.Eval() {
+		t.Errorf("open three not better: %v <= %v", open.Eval(), blocked.Eval())
+	}
+}
+
+func TestRadius(t *testing.T) {
+	s := play(t, Gomoku)
+	if moves := s.Moves(nil); len(moves) != 1 || moves[0] != 7*15+7 {
+		t.Errorf("first move not at the center: %v", moves)
+	}
+	if s.Move(Move{0, 0}) != nil || s.Move(Move{7, 8}) != nil || s.Move(Move{7, 7}) == nil {
+		t.Errorf("first move allowed off the center")
+	}
+	s = play(t, Gomoku, "H8")
+	if n := len(s.Next()); n != 24 {
+		t.Errorf("got %d next states, want 24", n)
+	}
+	if got, want := game.Perft(s, 3), game.Perft(struct{ game.State }{s}, 3); got != want {
+		t.Errorf("mover perft: got %d, want %d", got, want)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	// X must block the open four, or O wins
+	s := play(t, Gomoku, "H8", "A1", "H9", "A2", "H10", "A3", "H11")
+	r, _ := game.SearchOf(context.Background(), s, true,
+		game.Options{MaxDepth: 3, Table: game.NewTable(16), PVS: true})
+	if !r.Eval.IsWin() {
+		t.Errorf("open four not evaluated a win for O: %v", r.Eval)
+	}
+	// O wins in one
+	s = play(t, Gomoku, "H8", "A1", "H9", "A2", "H10", "A3", "H11", "A4")
+	r, _ = game.SearchOf(context.Background(), s, false,
+		game.Options{MaxDepth: 3, Table: game.NewTable(16)})
+	if r.Eval != game.WinIn(1) {
+		t.Errorf("got %v, want win in 1", r.Eval)
+	}
+	if m := r.Next.LastMove; m != (Move{7, 6}) && m != (Move{7, 11}) {
+		t.Errorf("winning move not found: %v", m)
+	}
+}
+
+func TestNewState(t *testing.T) {
+	for _, g := range []Game{
+		{M: 0, N: 3, K: 3}, {M: 3, N: 27, K: 3}, {M: 3, N: 3, K: 4}, {M: 3, N: 3, K: 3, Radius: -1},
+	} {
+		if _, err := g.NewState(); err == nil {
+			t.Errorf("%+v: bad game accepted", g)
+		}
+	}
+	s := play(t, Game{M: 4, N: 7, K: 4}, "D7")
+	if s.Move(Move{3, 6}) != nil || s.Move(Move{4, 0}) != nil {
+		t.Errorf("illegal move accepted")
+	}
+	if s1, s2 := play(t, TicTacToe, "A1", "B2", "C3"), play(t, TicTacToe, "C3", "B2", "A1"); s1.Hash() != s2.Hash() {
+		t.Errorf("transposed states hashed differently")
+	}
+}
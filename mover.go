@@ -0,0 +1,160 @@
+package game
+
+// Mover is implemented by a State that can be changed in place,
+// which saves allocating a next state for every move searched.
+// Search uses it when the search is serial.
+type Mover interface {
+	State
+	// Moves appends the moves allowed in the current state to moves
+	// and returns the extended slice.  The moves are small
+	// non-negative integers in the same order as the states returned
+	// by Next.  If the state implements MoveKeyer, each move
+	// equals the MoveKey of the state it leads to.
+	Moves(moves []int) []int
+	// Play makes the move, which must be allowed in the current state.
+	Play(move int)
+	// Undo takes back the last move played.
+	Undo()
+	// Clone returns an independent copy of the current state,
+	// which cannot undo the moves played before.
+	Clone() Mover
+}
+
+// play returns a copy of m after the move is made.
+func play(m Mover, move int) Mover {
+	t := m.Clone()
+	t.Play(move)
+	return t
+}
+
+// moverSearch runs an iteration of the serial search on m within (α, β)
+// by changing m in place.  It returns the best move, or -1 if there is
+// none, and the evaluation in the usual sense.  If pvs is false,
+// all moves are searched with the full window, as minmax does.
+func (sr *searcher) moverSearch(m Mover, depth uint, findMin bool, α, β Evaluation, pvs bool) (move int, eval Evaluation) {
+	sr.begin(depth)
+	for uint(len(sr.moves)) <= depth {
+		sr.moves = append(sr.moves, nil)
+		sr.mpv = append(sr.mpv, nil)
+	}
+	if findMin {
+		move, eval = sr.pvsMove(m, depth, -β, -α, -1, pvs)
+		return move, -eval
+	}
+	return sr.pvsMove(m, depth, α, β, 1, pvs)
+}
+
+// setMovePV is setPV for the principal variation of moves.
+func (sr *searcher) setMovePV(ply uint, move int) {
+	pv := append(sr.mpv[ply][:0], move)
+	if ply+1 < uint(len(sr.mpv)) {
+		pv = append(pv, sr.mpv[ply+1]...)
+	}
+	sr.mpv[ply] = pv
+}
+
+// pvsMove is pvs on a Mover, which is restored when it returns.
+// It returns the best move instead of the best next state.
+func (sr *searcher) pvsMove(m Mover, iterations uint, α, β Evaluation, sign int, pvs bool) (move int, eval Evaluation) {
+	move = -1
+	if sr.stop() {
+		return
+	}
+	ply := sr.depth - iterations
+	moves := m.Moves(sr.moves[ply][:0])
+	sr.moves[ply] = moves
+	if iterations == 0 || len(moves) == 0 {
+		if len(moves) != 0 {
+			sr.deeper = true
+		}
+		sr.mpv[ply] = sr.mpv[ply][:0]
+		eval = m.Eval()
+		if sign < 0 {
+			eval = -eval
+		}
+		return
+	}
+	hash, hashed := sr.hash(m)
+	first := -1
+	if hashed {
+		lo, hi := α, β
+		if sign < 0 {
+			lo, hi = -β, -α
+		}
+		var done bool
+		if first, eval, done = sr.probe(hash, len(moves), iterations, lo, hi); done {
+			move = moves[first]
+			if sign < 0 {
+				eval = -eval
+			}
+			sr.mpv[ply] = append(sr.mpv[ply][:0], move)
+			return
+		}
+	}
+	perm := sr.order(len(moves), ply, first, sign < 0, func(i int) int {
+		return moves[i]
+	})
+	deeper, α0, best := sr.deeper, α, 0
+	sr.deeper = false
+	eval = Lost
+	for i := range moves {
+		j := i
+		if perm != nil {
+			j = perm[i]
+		}
+		mv := moves[j]
+		m.Play(mv)
+		var e Evaluation
+		if i == 0 || !pvs {
+			_, e = sr.pvsMove(m, iterations-1, -sooner(β), -sooner(α), -sign, pvs)
+			e = (-e).Later()
+		} else {
+			_, e = sr.pvsMove(m, iterations-1, -sooner(α+1), -sooner(α), -sign, pvs)
+			e = (-e).Later()
+			if α < e && e < β && !sr.aborted {
+				_, e = sr.pvsMove(m, iterations-1, -sooner(β), -sooner(α), -sign, pvs)
+				e = (-e).Later()
+			}
+		}
+		m.Undo()
+		if sr.aborted {
+			return
+		}
+		if move < 0 || e > eval {
+			move = mv
+			eval = e
+			best = j
+			sr.setMovePV(ply, mv)
+			if e > α {
+				α = e
+				if α >= β {
+					sr.rewardKey(mv, ply, iterations, sign < 0)
+					sr.cutoffs++
+					break
+				}
+			}
+		}
+	}
+	if hashed {
+		bound := Exact
+		switch {
+		case eval <= α0:
+			bound = UpperBound
+		case eval >= β:
+			bound = LowerBound
+		}
+		e := eval
+		if sign < 0 {
+			e = -e
+			switch bound {
+			case UpperBound:
+				bound = LowerBound
+			case LowerBound:
+				bound = UpperBound
+			}
+		}
+		sr.record(hash, iterations, !sr.deeper, e, bound, best, nil)
+	}
+	sr.deeper = sr.deeper || deeper
+	return
+}
@@ -0,0 +1,133 @@
+package game
+
+import "sort"
+
+// MoveKeyer is implemented by a State that can identify the move
+// leading to it.  It enables the move ordering heuristics,
+// which try first the moves that caused cutoffs elsewhere.
+type MoveKeyer interface {
+	// MoveKey returns a small non-negative integer identifying
+	// the move that led to the state, e.g. the index of the cell
+	// where a piece is placed.
+	MoveKey() int
+}
+
+// killerSlots is the number of killer moves remembered at each ply.
+const killerSlots = 2
+
+// noKey is the key of a state that does not implement MoveKeyer.
+const noKey = -1
+
+// moveKey returns the move key of s, or noKey.
+func moveKey(s State) int {
+	if k, ok := s.(MoveKeyer); ok {
+		return k.MoveKey()
+	}
+	return noKey
+}
+
+// side returns the index of the player in history scores.
+func side(findMin bool) int {
+	if findMin {
+		return 1
+	}
+	return 0
+}
+
+// arrange orders nxt before it is searched at the given ply:
+// first the one at index first (usually the best one in the table),
+// then the killer moves at the ply, and then the others by their
+// history scores.  Otherwise the order of Next is kept.
+//
+// It returns the original index of each state in nxt,
+// or nil if nxt is not changed.
+func (sr *searcher) arrange(nxt []State, ply uint, first int, findMin bool) (perm []int) {
+	perm = sr.order(len(nxt), ply, first, findMin, func(i int) int {
+		return moveKey(nxt[i])
+	})
+	if perm != nil {
+		orig := append([]State(nil), nxt...)
+		for i, j := range perm {
+			nxt[i] = orig[j]
+		}
+	}
+	return
+}
+
+// order returns the order in which n moves are searched at the given
+// ply, as described in arrange, where key returns the key of a move.
+// It returns nil if the moves are searched in their original order.
+func (sr *searcher) order(n int, ply uint, first int, findMin bool, key func(i int) int) (perm []int) {
+	if sr.killers == nil && sr.history == nil {
+		if first <= 0 {
+			return nil
+		}
+		perm = make([]int, n)
+		for i := range perm {
+			perm[i] = i
+		}
+		perm[0], perm[first] = first, 0
+		return
+	}
+
+	const top = ^uint64(0)
+	prio := make([]uint64, n)
+	for i := range prio {
+		if i == first {
+			prio[i] = top
+			continue
+		}
+		k := key(i)
+		if k == noKey {
+			continue
+		}
+		if sr.killers != nil {
+			for slot, killer := range sr.killers[ply] {
+				if k == killer {
+					prio[i] = top - 1 - uint64(slot)
+				}
+			}
+		}
+		if prio[i] == 0 && sr.history != nil {
+			if h := sr.history[side(findMin)]; k < len(h) {
+				prio[i] = h[k]
+			}
+		}
+	}
+	perm = make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	sort.SliceStable(perm, func(a, b int) bool {
+		return prio[perm[a]] > prio[perm[b]]
+	})
+	return
+}
+
+// reward credits the move leading to t for a cutoff at the given ply,
+// with the given depth remaining.
+func (sr *searcher) reward(t State, ply, iterations uint, findMin bool) {
+	sr.rewardKey(moveKey(t), ply, iterations, findMin)
+}
+
+// rewardKey credits the move with key k, as reward does.
+func (sr *searcher) rewardKey(k int, ply, iterations uint, findMin bool) {
+	if k == noKey {
+		return
+	}
+	if sr.killers != nil {
+		ks := &sr.killers[ply]
+		if ks[0] != k {
+			copy(ks[1:], ks[:killerSlots-1])
+			ks[0] = k
+		}
+	}
+	if sr.history != nil {
+		h := sr.history[side(findMin)]
+		for len(h) <= k {
+			h = append(h, 0)
+		}
+		h[k] += uint64(iterations) * uint64(iterations)
+		sr.history[side(findMin)] = h
+	}
+}
@@ -0,0 +1,261 @@
+package othello
+
+import (
+	"github.com/z-rui/game"
+	"math/bits"
+)
+
+// BitState is the same as State, but it represents the board by
+// two bitboards, one for each player, where the cell (i, j)
+// corresponds to the bit i*N+j.  Legal moves and flips are found
+// by shifting the bitboards, which is much faster than walking
+// the board cell by cell.
+//
+// BitState works only with the standard board of size N.
+type BitState struct {
+	o, x     uint64
+	LastMove Move
+	Turn     Cell          // must be O or X
+	history  []bitSnapshot // states before the moves played by Play
+}
+
+// NewBitState returns a new state at the start of the game.
+func NewBitState() *BitState {
+	return NewState().BitState()
+}
+
+// BitState converts s to a BitState.
+// It panics if the board size of s is not N.
+func (s *State) BitState() *BitState {
+	if s.n() != N {
+		panic("othello: BitState requires the board size N")
+	}
+	b := &BitState{LastMove: s.LastMove, Turn: s.Turn}
+	for k, c := range s.Board {
+		switch c {
+		case O:
+			b.o |= 1 << uint(k)
+		case X:
+			b.x |= 1 << uint(k)
+		}
+	}
+	return b
+}
+
+// State converts b to a State.
+func (b *BitState) State() *State {
+	s := &State{Board: make([]Cell, N*N), Size: N, LastMove: b.LastMove, Turn: b.Turn}
+	for k := range s.Board {
+		s.Board[k] = b.cell(k/N, k%N)
+	}
+	s.countO, s.countX = b.Count()
+	s.counted = true
+	return s
+}
+
+func cellBit(i, j int) uint64 {
+	return 1 << uint(i*N+j)
+}
+
+func (b *BitState) cell(i, j int) Cell {
+	switch {
+	case b.o&cellBit(i, j) != 0:
+		return O
+	case b.x&cellBit(i, j) != 0:
+		return X
+	}
+	return Empty
+}
+
+// players returns the bitboards of the player to move
+// and the opponent.
+func (b *BitState) players() (p, q uint64) {
+	if b.Turn == O {
+		return b.o, b.x
+	}
+	return b.x, b.o
+}
+
+// Masks clearing the first and the last column.
+const (
+	notFirst uint64 = 0xfefefefefefefefe
+	notLast  uint64 = 0x7f7f7f7f7f7f7f7f
+)
+
+// shift moves every bit of x by one cell in the direction d,
+// which is one of the 8 directions numbered 0 through 7.
+func shift(x uint64, d int) uint64 {
+	switch d {
+	case 0: // right
+		return x << 1 & notFirst
+	case 1: // left
+		return x >> 1 & notLast
+	case 2: // down
+		return x << N
+	case 3: // up
+		return x >> N
+	case 4: // down right
+		return x << (N + 1) & notFirst
+	case 5: // down left
+		return x << (N - 1) & notLast
+	case 6: // up right
+		return x >> (N - 1) & notFirst
+	default: // up left
+		return x >> (N + 1) & notLast
+	}
+}
+
+// legal returns the bitboard of the cells where the player
+// with discs p can play against the opponent with discs q.
+func legal(p, q uint64) (m uint64) {
+	empty := ^(p | q)
+	for d := 0; d < 8; d++ {
+		t := shift(p, d) & q
+		for k := 0; k < N-3; k++ {
+			t |= shift(t, d) & q
+		}
+		m |= shift(t, d) & empty
+	}
+	return
+}
+
+// flips returns the bitboard of the discs flipped when the player
+// with discs p plays at mv against the opponent with discs q.
+func flips(p, q, mv uint64) (f uint64) {
+	for d := 0; d < 8; d++ {
+		var t uint64
+		x := shift(mv, d)
+		for x&q != 0 {
+			t |= x
+			x = shift(x, d)
+		}
+		if x&p != 0 {
+			f |= t
+		}
+	}
+	return
+}
+
+// Pass returns a new state after a player passes.
+func (b *BitState) Pass() *BitState {
+	return &BitState{o: b.o, x: b.x, LastMove: invalidMove, Turn: b.Turn ^ (O ^ X)}
+}
+
+// Count returns the counts of O's and X's on the board.
+func (b *BitState) Count() (o int, x int) {
+	return bits.OnesCount64(b.o), bits.OnesCount64(b.x)
+}
+
+// Dim returns the dimension of the board
+func (b *BitState) Dim() (rows int, cols int) {
+	return N, N
+}
+
+// Get returns the string representation at (i, j)
+func (b *BitState) Get(i, j int) string {
+	return b.cell(i, j).String()
+}
+
+// Eval returns the evaluation of the current state.
+func (b *BitState) Eval() (eval game.Evaluation) {
+	if b.IsEnd() {
+		o, x := b.Count()
+		switch {
+		case o > x:
+			eval = game.Won
+		case o < x:
+			eval = game.Lost
+		}
+		return
+	}
+	for o := b.o; o != 0; o &= o - 1 {
+		k := bits.TrailingZeros64(o)
+		eval += game.Evaluation(valueMaps[N][k/N][k%N])
+	}
+	for x := b.x; x != 0; x &= x - 1 {
+		k := bits.TrailingZeros64(x)
+		eval -= game.Evaluation(valueMaps[N][k/N][k%N])
+	}
+	return
+}
+
+// IsEnd tells if the game has ended,
+// i.e. neither player can move.
+func (b *BitState) IsEnd() bool {
+	p, q := b.players()
+	return legal(p, q) == 0 && legal(q, p) == 0
+}
+
+// MustPass tells if the current user must pass.
+func (b *BitState) MustPass() bool {
+	return legal(b.players()) == 0
+}
+
+// Next returns all possible next states,
+// in the same order as State.Next.
+func (b *BitState) Next() (nxt []game.State) {
+	p, q := b.players()
+	m := legal(p, q)
+	nxt = make([]game.State, 0, bits.OnesCount64(m)+1)
+	for _, mv := range validMoves[N] {
+		if m&cellBit(int(mv.I), int(mv.J)) != 0 {
+			nxt = append(nxt, b.play(mv, p, q))
+		}
+	}
+	if len(nxt) == 0 && legal(q, p) != 0 {
+		nxt = append(nxt, b.Pass())
+	}
+	return
+}
+
+// Move returns the next state based on the move.
+// It returns nil if the move is not allowed.
+func (b *BitState) Move(m Move) *BitState {
+	if m.I >= N || m.J >= N {
+		return nil
+	}
+	p, q := b.players()
+	if legal(p, q)&cellBit(int(m.I), int(m.J)) == 0 {
+		return nil
+	}
+	return b.play(m, p, q)
+}
+
+// play plays a legal move for the player with discs p
+// against the opponent with discs q.
+func (b *BitState) play(m Move, p, q uint64) *BitState {
+	mv := cellBit(int(m.I), int(m.J))
+	f := flips(p, q, mv)
+	p |= mv | f
+	q &^= f
+	t := &BitState{LastMove: m, Turn: b.Turn ^ (O ^ X)}
+	if b.Turn == O {
+		t.o, t.x = p, q
+	} else {
+		t.o, t.x = q, p
+	}
+	return t
+}
+
+// Hash returns the Zobrist hash of the state,
+// which is the same as that of the corresponding State.
+func (b *BitState) Hash() (h uint64) {
+	for o := b.o; o != 0; o &= o - 1 {
+		k := bits.TrailingZeros64(o)
+		h ^= zobristCell[k/N][k%N][0]
+	}
+	for x := b.x; x != 0; x &= x - 1 {
+		k := bits.TrailingZeros64(x)
+		h ^= zobristCell[k/N][k%N][1]
+	}
+	if b.Turn == X {
+		h ^= zobristX
+	}
+	return
+}
+
+// MoveKey returns the index of the cell where the last move was placed.
+// A pass has a key beyond those of the cells.
+func (b *BitState) MoveKey() int {
+	return int(b.LastMove.I)*MaxN + int(b.LastMove.J)
+}
@@ -0,0 +1,77 @@
+package othello
+
+import (
+	"context"
+	"github.com/z-rui/game"
+	"math/rand"
+	"testing"
+)
+
+// sameState tells if a State and a BitState represent the same state.
+func sameState(s *State, b *BitState) bool {
+	t := b.State()
+	o, x := s.Count()
+	bo, bx := b.Count()
+	return sameBoard(s.Board, t.Board) && o == bo && x == bx &&
+		s.LastMove == b.LastMove && s.Turn == b.Turn
+}
+
+func TestBitState(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 100; n++ {
+		s, b := NewState(), NewBitState()
+		for {
+			if !sameState(s, b) {
+				t.Fatalf("state mismatch:\n%v\n%v", s, b.State())
+			}
+			if s.Eval() != b.Eval() || s.IsEnd() != b.IsEnd() ||
+				s.MustPass() != b.MustPass() || s.Hash() != b.Hash() {
+				t.Fatalf("method mismatch on %v", s)
+			}
+			nxt, bnxt := s.Next(), b.Next()
+			if len(nxt) != len(bnxt) {
+				t.Fatalf("%d next states, want %d: %v", len(bnxt), len(nxt), s)
+			}
+			for i := range nxt {
+				if !sameState(nxt[i].(*State), bnxt[i].(*BitState)) {
+					t.Fatalf("next state %d mismatch: %v", i, s)
+				}
+			}
+			if len(nxt) == 0 {
+				break
+			}
+			// also try an illegal move
+			m := Move{uint8(r.Intn(N)), uint8(r.Intn(N))}
+			if (s.Move(m) == nil) != (b.Move(m) == nil) {
+				t.Fatalf("move %v mismatch: %v", m, s)
+			}
+			k := r.Intn(len(nxt))
+			s, b = nxt[k].(*State), bnxt[k].(*BitState)
+		}
+	}
+}
+
+// benchmarkNPS searches midgame positions,
+// and reports the number of nodes visited per second.
+func benchmarkNPS(b *testing.B, convert func(*State) game.State) {
+	var nodes uint64
+	var seconds float64
+	for i := 0; i < b.N; i++ {
+		for _, moves := range []int{10, 20, 30} {
+			s := midgame(moves)
+			opt := game.Options{MaxDepth: 6, Table: game.NewTable(16)}
+			r, _ := game.Search(context.Background(), convert(s), s.Turn == X, opt)
+			nodes += r.Nodes
+			seconds += r.Elapsed.Seconds()
+		}
+	}
+	b.ReportMetric(float64(nodes)/seconds, "nodes/s")
+}
+
+func BenchmarkArrayNPS(b *testing.B) {
+	benchmarkNPS(b, func(s *State) game.State { return s })
+}
+
+func BenchmarkBitboardNPS(b *testing.B) {
+	benchmarkNPS(b, func(s *State) game.State { return s.BitState() })
+}
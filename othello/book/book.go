@@ -0,0 +1,262 @@
+// Package book provides an opening book for the Othello game:
+// a collection of lines, i.e. sequences of moves from the start
+// of the game, each with a weight telling how often it is played.
+//
+// A book file is text with one line of play per row, such as
+//
+//	C4 C3 D3 C5 10
+//
+// where the optional number at the end is the weight (1 by default).
+// Rows that are empty or start with # are ignored.
+//
+// The book recognizes positions up to the symmetries of the board,
+// so that a line also covers its rotations and reflections.
+// It is for the standard board of size othello.N.
+package book
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/z-rui/game"
+	"github.com/z-rui/game/othello"
+	"io"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Book is an opening book.  The zero value is not usable;
+// use New or Load to create one.
+type Book struct {
+	lines map[string]uint                  // weight of each line as added
+	moves map[uint64]map[othello.Move]uint // weight of the moves in each position
+	order map[uint64][]othello.Move        // moves in each position in the order added
+}
+
+// New returns an empty book.
+func New() *Book {
+	return &Book{
+		lines: make(map[string]uint),
+		moves: make(map[uint64]map[othello.Move]uint),
+		order: make(map[uint64][]othello.Move),
+	}
+}
+
+// Load reads a book file.
+func Load(r io.Reader) (*Book, error) {
+	b := New()
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		weight := uint64(1)
+		if w, err := strconv.ParseUint(fields[len(fields)-1], 10, 0); err == nil {
+			weight = w
+			fields = fields[:len(fields)-1]
+		}
+		line := make([]othello.Move, len(fields))
+		for i, f := range fields {
+			m, err := othello.ParseMove(f)
+			if err != nil {
+				return nil, fmt.Errorf("book: line %d: %v", n, err)
+			}
+			line[i] = m
+		}
+		if err := b.Add(line, uint(weight)); err != nil {
+			return nil, fmt.Errorf("book: line %d: %v", n, err)
+		}
+	}
+	return b, sc.Err()
+}
+
+// Add adds a line with the given weight to the book.
+// It fails if a move of the line is not allowed.
+func (b *Book) Add(line []othello.Move, weight uint) error {
+	if len(line) == 0 || weight == 0 {
+		return nil
+	}
+	// the symmetries of the board map the line to at most 8 lines,
+	// of which those starting from the initial position are added
+	added := make(map[string]bool)
+	for k := othello.Identity; k < othello.Transforms; k++ {
+		t := make([]othello.Move, len(line))
+		for i, m := range line {
+			t[i] = k.Move(m, othello.N)
+		}
+		key := lineKey(t)
+		if added[key] {
+			continue
+		}
+		added[key] = true
+		if err := b.add(t, weight); err != nil && k == othello.Identity {
+			return err
+		}
+	}
+	b.lines[lineKey(line)] += weight
+	return nil
+}
+
+// add adds the weight to each move of the line, which is not
+// added at all if a move is not allowed.
+func (b *Book) add(line []othello.Move, weight uint) error {
+	hashes := make([]uint64, len(line))
+	s := othello.NewState()
+	for i, m := range line {
+		hashes[i] = s.Hash()
+		if s = play(s, m); s == nil {
+			return fmt.Errorf("move %d (%v) is not allowed", i+1, m)
+		}
+	}
+	for i, m := range line {
+		w := b.moves[hashes[i]]
+		if w == nil {
+			w = make(map[othello.Move]uint)
+			b.moves[hashes[i]] = w
+		}
+		if w[m] == 0 {
+			b.order[hashes[i]] = append(b.order[hashes[i]], m)
+		}
+		w[m] += weight
+	}
+	return nil
+}
+
+// play returns the state after the move, which may be a pass,
+// or nil if the move is not allowed.
+func play(s *othello.State, m othello.Move) *othello.State {
+	if !m.Valid() {
+		if !s.MustPass() || s.IsEnd() {
+			return nil
+		}
+		return s.Pass()
+	}
+	return s.Move(m)
+}
+
+// Moves returns the moves in the book for s, with their weights.
+// It returns an empty map if s is out of the book.
+func (b *Book) Moves(s *othello.State) map[othello.Move]uint {
+	moves := make(map[othello.Move]uint)
+	for m, w := range b.moves[s.Hash()] {
+		moves[m] = w
+	}
+	return moves
+}
+
+// Pick chooses a move in the book for s at random, with the
+// probability of each move proportional to its weight.
+// It returns the next state, or nil if s is out of the book.
+func (b *Book) Pick(s *othello.State, r *rand.Rand) *othello.State {
+	h := s.Hash()
+	var total uint64
+	for _, w := range b.moves[h] {
+		total += uint64(w)
+	}
+	if total == 0 {
+		return nil
+	}
+	x := uint64(r.Int63n(int64(total)))
+	for _, m := range b.order[h] {
+		w := uint64(b.moves[h][m])
+		if x < w {
+			return play(s, m)
+		}
+		x -= w
+	}
+	return nil
+}
+
+// Len returns the number of lines in the book.
+func (b *Book) Len() int {
+	return len(b.lines)
+}
+
+// Write writes the book in the format read by Load,
+// with the lines in lexical order.
+func (b *Book) Write(w io.Writer) error {
+	keys := make([]string, 0, len(b.lines))
+	for k := range b.lines {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	bw := bufio.NewWriter(w)
+	for _, k := range keys {
+		fmt.Fprintln(bw, k, b.lines[k])
+	}
+	return bw.Flush()
+}
+
+func lineKey(line []othello.Move) string {
+	s := make([]string, len(line))
+	for i, m := range line {
+		s[i] = m.String()
+	}
+	return strings.Join(s, " ")
+}
+
+// Generate builds a book from the given number of self-play games,
+// each recorded for the given number of plies.  At each ply,
+// the next states are searched to the given depth, and one is
+// chosen at random among those within margin of the best.
+func Generate(games, plies int, depth uint, margin game.Evaluation, r *rand.Rand) *Book {
+	b := New()
+	table := game.NewTable(16)
+	for g := 0; g < games; g++ {
+		s := othello.NewState()
+		var line []othello.Move
+		for len(line) < plies {
+			t := choose(s, depth, margin, table, r)
+			if t == nil {
+				break
+			}
+			s = t
+			line = append(line, s.LastMove)
+		}
+		b.Add(line, 1)
+	}
+	return b
+}
+
+// choose searches each next state of s and returns one at random
+// among those within margin of the best, or nil if there is none.
+func choose(s *othello.State, depth uint, margin game.Evaluation, table *game.Table, r *rand.Rand) *othello.State {
+	findMin := s.Turn == othello.X
+	var (
+		nxt   []*othello.State
+		evals []game.Evaluation
+		best  game.Evaluation
+	)
+	for _, u := range s.Next() {
+		t := u.(*othello.State)
+		var e game.Evaluation
+		if depth > 1 {
+			res, _ := othello.Search(context.Background(), t, !findMin,
+				game.Options{MaxDepth: depth - 1, Table: table, PVS: true})
+			e = res.Eval
+		} else {
+			e = t.Eval()
+		}
+		if findMin {
+			e = -e
+		}
+		if len(nxt) == 0 || e > best {
+			best = e
+		}
+		nxt = append(nxt, t)
+		evals = append(evals, e)
+	}
+	var good []*othello.State
+	for i, t := range nxt {
+		if int64(best)-int64(evals[i]) <= int64(margin) {
+			good = append(good, t)
+		}
+	}
+	if len(good) == 0 {
+		return nil
+	}
+	return good[r.Intn(len(good))]
+}
@@ -0,0 +1,89 @@
+package book
+
+import (
+	"bytes"
+	"github.com/z-rui/game/othello"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	b, err := Load(strings.NewReader("# comment\n\nC5 C6 3\nC5 C4\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := othello.NewState()
+	c5, _ := othello.ParseMove("C5")
+	if got := b.Moves(s)[c5]; got != 4 {
+		t.Errorf("C5: got weight %d, want 4", got)
+	}
+	s = s.Move(c5)
+	c6, _ := othello.ParseMove("C6")
+	c4, _ := othello.ParseMove("C4")
+	if m := b.Moves(s); m[c6] != 3 || m[c4] != 1 || len(m) != 2 {
+		t.Errorf("after C5: got %v", m)
+	}
+
+	var buf bytes.Buffer
+	b.Write(&buf)
+	if got, want := buf.String(), "C5 C4 1\nC5 C6 3\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	for _, bad := range []string{"C5 C5\n", "Z9\n"} {
+		if _, err := Load(strings.NewReader(bad)); err == nil {
+			t.Errorf("%q: illegal line accepted", bad)
+		}
+	}
+}
+
+func TestSymmetry(t *testing.T) {
+	b, _ := Load(strings.NewReader("C5 C6\n"))
+	// every first move is a symmetry of C5
+	s := othello.NewState()
+	for _, u := range s.Next() {
+		first := u.(*othello.State)
+		if b.Moves(s)[first.LastMove] != 1 {
+			t.Errorf("%v not in the book", first.LastMove)
+		}
+		if len(b.Moves(first)) != 1 {
+			t.Errorf("no reply to %v in the book", first.LastMove)
+		}
+	}
+}
+
+func TestPick(t *testing.T) {
+	b, _ := Load(strings.NewReader("C5 C6 3\nC5 C4 1\n"))
+	r := rand.New(rand.NewSource(1))
+	c5, _ := othello.ParseMove("C5")
+	s := othello.NewState().Move(c5)
+	count := make(map[othello.Move]int)
+	for i := 0; i < 4000; i++ {
+		count[b.Pick(s, r).LastMove]++
+	}
+	c6, _ := othello.ParseMove("C6")
+	if n := count[c6]; n < 2800 || n > 3200 {
+		t.Errorf("C6 picked %d times out of 4000, want about 3000", n)
+	}
+	if t2 := b.Pick(othello.NewState().Move(c5).Move(c6), r); t2 != nil {
+		t.Errorf("out of book position picked %v", t2.LastMove)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	b := Generate(4, 6, 2, 10, rand.New(rand.NewSource(1)))
+	if b.Len() == 0 {
+		t.Fatal("no line generated")
+	}
+	r := rand.New(rand.NewSource(1))
+	s := othello.NewState()
+	for ply := 0; ply < 6; ply++ {
+		if s = b.Pick(s, r); s == nil {
+			t.Fatalf("out of book at ply %d", ply)
+		}
+	}
+	if Default().Len() == 0 {
+		t.Error("default book is empty")
+	}
+}
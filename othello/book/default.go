@@ -0,0 +1,19 @@
+package book
+
+import (
+	_ "embed"
+	"strings"
+)
+
+//go:embed default.book
+var defaultBook string
+
+// Default returns the book built into the package,
+// which was generated from self-play games.
+func Default() *Book {
+	b, err := Load(strings.NewReader(defaultBook))
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,103 @@
+package othello
+
+import (
+	"context"
+	"github.com/z-rui/game"
+)
+
+// position is a State or a BitState.
+type position interface {
+	game.Mover
+	game.Hasher
+	game.MoveKeyer
+	Count() (o int, x int)
+}
+
+// endgame wraps a position, so that it evaluates to the disc
+// differential, which is exact at the end of the game.
+type endgame struct {
+	position
+}
+
+// Eval returns the number of O's minus the number of X's.
+func (e endgame) Eval() game.Evaluation {
+	o, x := e.Count()
+	return game.Evaluation(o) - game.Evaluation(x)
+}
+
+// Next returns all possible next states, wrapped.
+func (e endgame) Next() []game.State {
+	nxt := e.position.Next()
+	for i, t := range nxt {
+		nxt[i] = endgame{t.(position)}
+	}
+	return nxt
+}
+
+// Hash returns a hash different from that of the position, so that
+// the disc differentials do not mix with the evaluations of other
+// searches sharing a Table.
+func (e endgame) Hash() uint64 {
+	return e.position.Hash() ^ zobristEndgame
+}
+
+// Clone returns a copy of the current state, wrapped.
+func (e endgame) Clone() game.Mover {
+	return endgame{e.position.Clone().(position)}
+}
+
+// state converts the position back to a State.
+func (e endgame) state() *State {
+	if b, ok := e.position.(*BitState); ok {
+		return b.State()
+	}
+	return e.position.(*State)
+}
+
+// Empties returns the number of empty cells on the board.
+func (s *State) Empties() int {
+	o, x := s.Count()
+	return len(s.Board) - o - x
+}
+
+// Solve searches s to the end of the game, assuming both players
+// play perfectly.  It returns the final disc differential
+// (the number of O's minus the number of X's) and the line of play,
+// which starts with the best next state and ends at the end of the game.
+// The line is empty if the game has ended at s.
+//
+// The search uses opt.Table, opt.Workers and the move ordering options;
+// opt.MaxDepth is ignored.  opt.Budget limits the time taken, as ctx
+// does.  If the search is stopped before the end is reached, Solve
+// returns the error, and diff and line are not exact.
+//
+// The time taken grows exponentially with the number of empty cells;
+// Solve is meant for the last dozen moves or so.
+func Solve(ctx context.Context, s *State, opt game.Options) (diff int, line []*State, err error) {
+	if opt.Budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opt.Budget)
+		defer cancel()
+	}
+	opt.MaxDepth, opt.Budget = 0, 0
+	for t := s; ; {
+		// the standard board is searched faster as a BitState
+		var p position = t
+		if t.n() == N {
+			p = t.BitState()
+		}
+		r, err := game.SearchOf(ctx, endgame{p}, t.Turn == X, opt)
+		if t == s {
+			diff = int(r.Eval)
+		}
+		for _, u := range r.PV {
+			line = append(line, u.state())
+		}
+		if err != nil || len(r.PV) == 0 {
+			return diff, line, err
+		}
+		// the principal variation may stop short of the end,
+		// if the rest is not in the table any more
+		t = line[len(line)-1]
+	}
+}
@@ -0,0 +1,40 @@
+package othello
+
+import "math/rand"
+
+// Zobrist keys for hashing a state.
+var (
+	zobristCell    [MaxN][MaxN][2]uint64 // for O and X at each cell
+	zobristX       uint64                // for X's turn
+	zobristEndgame uint64                // for the search by Solve
+)
+
+// Zobrist keys generation
+func init() {
+	r := rand.New(rand.NewSource(N))
+	for i := 0; i < MaxN; i++ {
+		for j := 0; j < MaxN; j++ {
+			zobristCell[i][j][0] = r.Uint64()
+			zobristCell[i][j][1] = r.Uint64()
+		}
+	}
+	zobristX = r.Uint64()
+	zobristEndgame = r.Uint64()
+}
+
+// Hash returns the Zobrist hash of the state.
+func (s *State) Hash() (h uint64) {
+	n := s.n()
+	for k, c := range s.Board {
+		switch c {
+		case O:
+			h ^= zobristCell[k/n][k%n][0]
+		case X:
+			h ^= zobristCell[k/n][k%n][1]
+		}
+	}
+	if s.Turn == X {
+		h ^= zobristX
+	}
+	return
+}
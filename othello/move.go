@@ -1,6 +1,10 @@
 package othello
 
-import "sort"
+import (
+	"errors"
+	"sort"
+	"strconv"
+)
 
 // Move represents a position on the board
 type Move struct {
@@ -12,47 +16,75 @@ func (m Move) String() string {
 	if m == invalidMove {
 		return "(pass)"
 	}
-	return string([]byte{byte(m.I) + 'A', byte(m.J) + '1'})
+	return string(rune(m.I)+'A') + strconv.Itoa(int(m.J)+1)
+}
+
+// ParseMove converts the string representation of a move,
+// such as "C4", back to a Move.  It does not tell if the move
+// is on a board of a particular size.  "(pass)" is parsed as a pass.
+func ParseMove(str string) (Move, error) {
+	if str == invalidMove.String() {
+		return invalidMove, nil
+	}
+	if len(str) < 2 {
+		return invalidMove, errors.New("othello: bad move " + str)
+	}
+	j, err := strconv.Atoi(str[1:])
+	if err != nil || j < 1 || j > MaxN {
+		return invalidMove, errors.New("othello: bad move " + str)
+	}
+	m := Move{str[0] - 'A', uint8(j - 1)}
+	if str[0] >= 'a' {
+		m.I = str[0] - 'a'
+	}
+	if !m.Valid() {
+		return invalidMove, errors.New("othello: bad move " + str)
+	}
+	return m, nil
 }
 
 // invalidMove represents an invalid move;
 // also for representing a pass.
-var invalidMove = Move{N, N}
+var invalidMove = Move{MaxN, MaxN}
 
-// validMoves contains all valid moves,
-// ordered by the value assigned in valueMap
-var validMoves [N * N]Move
+// validMoves contains all valid moves on the board of each size,
+// ordered by the value assigned in valueMaps
+var validMoves [MaxN + 1][]Move
 
 // validMoves generation
 func init() {
-	var i, j, k uint8
-	for i = 0; i < N; i++ {
-		for j = 0; j < N; j++ {
-			validMoves[k] = Move{i, j}
-			k++
+	for n := MinN; n <= MaxN; n += 2 {
+		moves := make([]Move, 0, n*n)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				moves = append(moves, Move{uint8(i), uint8(j)})
+			}
 		}
+		valueMap := &valueMaps[n]
+		sort.Slice(moves, func(i, j int) bool {
+			x := moves[i]
+			y := moves[j]
+			return valueMap[x.I][x.J] > valueMap[y.I][y.J]
+		})
+		validMoves[n] = moves
 	}
-	sort.Slice(validMoves[:], func(i, j int) bool {
-		x := validMoves[i]
-		y := validMoves[j]
-		return x.value() > y.value()
-	})
-}
-
-func (m Move) value() int8 {
-	return valueMap[m.I][m.J]
 }
 
 // Valid tells if the move is a valid position (not out-of-bound)
-// on the board.
+// on the largest board.
 func (m Move) Valid() bool {
-	return 0 <= m.I && m.I < N && 0 <= m.J && m.J < N
+	return 0 <= m.I && m.I < MaxN && 0 <= m.J && m.J < MaxN
 }
 
 // Allowed tells if the move is allowed according to the game's rule.
 func (m Move) Allowed(s *State) bool {
+	return s.allowed(s.Turn, m)
+}
+
+// allowed tells if player p can make the move.
+func (s *State) allowed(p Cell, m Move) bool {
 	i, j := int(m.I), int(m.J)
-	if s.Board[i][j] != Empty {
+	if !s.contains(m) || s.Board[i*s.n()+j] != Empty {
 		return false
 	}
 	for di := -1; di <= 1; di++ {
@@ -60,10 +92,16 @@ func (m Move) Allowed(s *State) bool {
 			if di == 0 && dj == 0 {
 				continue
 			}
-			if s.match(i, j, di, dj) > 0 {
+			if s.match(p, i, j, di, dj) > 0 {
 				return true
 			}
 		}
 	}
 	return false
 }
+
+// MoveKey returns the index of the cell where the last move was placed.
+// A pass has a key beyond those of the cells.
+func (s *State) MoveKey() int {
+	return int(s.LastMove.I)*MaxN + int(s.LastMove.J)
+}
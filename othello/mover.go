@@ -0,0 +1,149 @@
+package othello
+
+import "github.com/z-rui/game"
+
+// passMove is the move of a pass, which is the same as its MoveKey.
+const passMove = MaxN*MaxN + MaxN
+
+// snapshot is what Undo restores in a State, besides the board,
+// which is saved in State.boards.
+type snapshot struct {
+	lastMove       Move
+	countO, countX int
+}
+
+// Moves appends the moves allowed in the current state to moves,
+// in the same order as Next.  A move is the index of the cell
+// on the largest board, i*MaxN+j, or passMove for a pass.
+func (s *State) Moves(moves []int) []int {
+	n := len(moves)
+	for _, mv := range validMoves[s.n()] {
+		if mv.Allowed(s) {
+			moves = append(moves, int(mv.I)*MaxN+int(mv.J))
+		}
+	}
+	if len(moves) == n && !s.IsEnd() {
+		moves = append(moves, passMove)
+	}
+	return moves
+}
+
+// Play makes the move in place.
+func (s *State) Play(move int) {
+	if !s.counted {
+		s.countO, s.countX = s.Count()
+		s.counted = true
+	}
+	s.history = append(s.history, snapshot{s.LastMove, s.countO, s.countX})
+	s.boards = append(s.boards, s.Board...)
+	if move == passMove {
+		s.LastMove = invalidMove
+		s.Turn ^= O ^ X
+		return
+	}
+	i, j := move/MaxN, move%MaxN
+	for di := -1; di <= 1; di++ {
+		for dj := -1; dj <= 1; dj++ {
+			if di == 0 && dj == 0 {
+				continue
+			}
+			// the directions do not share cells, so flipping one
+			// does not change the match of another
+			if n := s.match(s.Turn, i, j, di, dj); n > 0 {
+				s.flip(i, j, di, dj, n)
+			}
+		}
+	}
+	s.Board[i*s.n()+j] = s.Turn
+	if s.Turn == O {
+		s.countO++
+	} else {
+		s.countX++
+	}
+	s.LastMove = Move{uint8(i), uint8(j)}
+	s.Turn ^= O ^ X
+}
+
+// Undo takes back the last move played.
+func (s *State) Undo() {
+	n := len(s.history) - 1
+	h := s.history[n]
+	s.LastMove, s.countO, s.countX = h.lastMove, h.countO, h.countX
+	s.history = s.history[:n]
+	k := len(s.boards) - len(s.Board)
+	copy(s.Board, s.boards[k:])
+	s.boards = s.boards[:k]
+	s.Turn ^= O ^ X
+}
+
+// Clone returns a copy of the current state.
+func (s *State) Clone() game.Mover {
+	t := *s
+	t.Board = append([]Cell(nil), s.Board...)
+	t.history = nil
+	t.boards = nil
+	return &t
+}
+
+// bitSnapshot is what Undo restores in a BitState.
+type bitSnapshot struct {
+	o, x     uint64
+	lastMove Move
+}
+
+// Moves appends the moves allowed in the current state to moves,
+// in the same order as State.Moves.
+func (b *BitState) Moves(moves []int) []int {
+	p, q := b.players()
+	m := legal(p, q)
+	if m == 0 {
+		if legal(q, p) != 0 {
+			moves = append(moves, passMove)
+		}
+		return moves
+	}
+	for _, mv := range validMoves[N] {
+		if m&cellBit(int(mv.I), int(mv.J)) != 0 {
+			moves = append(moves, int(mv.I)*MaxN+int(mv.J))
+		}
+	}
+	return moves
+}
+
+// Play makes the move in place.
+func (b *BitState) Play(move int) {
+	b.history = append(b.history, bitSnapshot{b.o, b.x, b.LastMove})
+	if move == passMove {
+		b.LastMove = invalidMove
+		b.Turn ^= O ^ X
+		return
+	}
+	p, q := b.players()
+	mv := cellBit(move/MaxN, move%MaxN)
+	f := flips(p, q, mv)
+	p |= mv | f
+	q &^= f
+	if b.Turn == O {
+		b.o, b.x = p, q
+	} else {
+		b.o, b.x = q, p
+	}
+	b.LastMove = Move{uint8(move / MaxN), uint8(move % MaxN)}
+	b.Turn ^= O ^ X
+}
+
+// Undo takes back the last move played.
+func (b *BitState) Undo() {
+	n := len(b.history) - 1
+	h := b.history[n]
+	b.o, b.x, b.LastMove = h.o, h.x, h.lastMove
+	b.history = b.history[:n]
+	b.Turn ^= O ^ X
+}
+
+// Clone returns a copy of the current state.
+func (b *BitState) Clone() game.Mover {
+	t := *b
+	t.history = nil
+	return &t
+}
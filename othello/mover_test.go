@@ -0,0 +1,97 @@
+package othello
+
+import (
+	"context"
+	"github.com/z-rui/game"
+	"testing"
+)
+
+// noMover hides the Mover methods of a State,
+// so that it is searched through Next.
+type noMover struct {
+	hashState
+}
+
+type hashState interface {
+	game.State
+	game.Hasher
+	game.MoveKeyer
+}
+
+func TestMoverPerft(t *testing.T) {
+	for _, s := range []*State{NewState(), midgame(20)} {
+		for depth := uint(1); depth <= 5; depth++ {
+			want := game.Perft(noMover{s}, depth)
+			if got := game.Perft(s, depth); got != want {
+				t.Errorf("depth %d: got %d, want %d", depth, got, want)
+			}
+			if got := game.Perft(s.BitState(), depth); got != want {
+				t.Errorf("bitboard depth %d: got %d, want %d", depth, got, want)
+			}
+		}
+	}
+}
+
+func TestMoverSearch(t *testing.T) {
+	for _, moves := range []int{10, 20, 30} {
+		s := midgame(moves)
+		before := s.clone()
+		findMin := s.Turn == X
+		for _, opt := range []game.Options{
+			{MaxDepth: 5},
+			{MaxDepth: 5, PVS: true, Table: game.NewTable(16)},
+			{MaxDepth: 5, PVS: true, Table: game.NewTable(16), Killers: true, History: true},
+		} {
+			want, _ := game.Search(context.Background(), noMover{s}, findMin, opt)
+			if opt.Table != nil {
+				opt.Table.Clear()
+			}
+			for _, m := range []game.State{s, s.BitState()} {
+				r, _ := game.Search(context.Background(), m, findMin, opt)
+				if opt.Table != nil {
+					opt.Table.Clear()
+				}
+				if r.Eval != want.Eval || r.Nodes != want.Nodes {
+					t.Errorf("after %d moves, %+v: got %v in %d nodes, want %v in %d nodes",
+						moves, opt, r.Eval, r.Nodes, want.Eval, want.Nodes)
+				}
+				if len(r.PV) != len(want.PV) {
+					t.Errorf("after %d moves, %+v: got PV of %d states, want %d",
+						moves, opt, len(r.PV), len(want.PV))
+				}
+			}
+		}
+		if len(s.history) != 0 || !sameBoard(s.Board, before.Board) || s.Turn != before.Turn {
+			t.Errorf("after %d moves: search changed the root", moves)
+		}
+	}
+}
+
+// benchmarkAllocs searches midgame positions,
+// and reports the allocations per search.
+func benchmarkAllocs(b *testing.B, convert func(*State) game.State) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, moves := range []int{10, 20, 30} {
+			s := convert(midgame(moves))
+			opt := game.Options{MaxDepth: 5, PVS: true, Table: game.NewTable(16)}
+			game.Search(context.Background(), s, moves%2 != 0, opt)
+		}
+	}
+}
+
+func BenchmarkNextAllocs(b *testing.B) {
+	benchmarkAllocs(b, func(s *State) game.State { return noMover{s} })
+}
+
+func BenchmarkMoverAllocs(b *testing.B) {
+	benchmarkAllocs(b, func(s *State) game.State { return s })
+}
+
+func BenchmarkBitboardNextAllocs(b *testing.B) {
+	benchmarkAllocs(b, func(s *State) game.State { return noMover{s.BitState()} })
+}
+
+func BenchmarkBitboardMoverAllocs(b *testing.B) {
+	benchmarkAllocs(b, func(s *State) game.State { return s.BitState() })
+}
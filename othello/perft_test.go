@@ -0,0 +1,66 @@
+package othello
+
+import (
+	"github.com/z-rui/game"
+	"testing"
+)
+
+// perftTable lists the known numbers of leaves of the game tree
+// from the start of the game.
+var perftTable = []uint64{
+	1, 4, 12, 56, 244, 1396, 8200, 55092, 390216, 3005288,
+}
+
+func TestPerft(t *testing.T) {
+	for depth, want := range perftTable {
+		if testing.Short() && depth > 7 {
+			break
+		}
+		if got := game.Perft(NewBitState(), uint(depth)); got != want {
+			t.Errorf("bitboard depth %d: got %d, want %d", depth, got, want)
+		}
+		if depth > 8 {
+			// too slow
+			continue
+		}
+		if got := game.Perft(NewState(), uint(depth)); got != want {
+			t.Errorf("depth %d: got %d, want %d", depth, got, want)
+		}
+	}
+}
+
+func TestPerftPass(t *testing.T) {
+	// X cannot move, and O can play anywhere in the fourth column
+	s := State{Board: make([]Cell, N*N)}
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			switch {
+			case j < 3, j == N-1:
+				s.Board[i*N+j] = O
+			case j > 3:
+				s.Board[i*N+j] = X
+			}
+		}
+	}
+	s.LastMove = Move{0, 0}
+	s.Turn = X
+	for depth, want := range []uint64{1, 1, N} {
+		if got := game.Perft(&s, uint(depth)); got != want {
+			t.Errorf("depth %d: got %d, want %d", depth, got, want)
+		}
+	}
+	for depth := uint(3); depth <= 6; depth++ {
+		want := game.Perft(&s, depth)
+		if got := game.Perft(s.BitState(), depth); got != want {
+			t.Errorf("bitboard depth %d: got %d, want %d", depth, got, want)
+		}
+	}
+
+	// the game ends when neither player can move
+	for i := 0; i < N; i++ {
+		s.Board[i*N+N-1] = X
+	}
+	if got := game.Perft(&s, 1); got != 0 {
+		t.Errorf("ended game: got %d, want 0", got)
+	}
+}
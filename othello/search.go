@@ -0,0 +1,23 @@
+package othello
+
+import (
+	"context"
+	"github.com/z-rui/game"
+)
+
+// Search is game.SearchOf for a State.  On the standard board,
+// it searches the corresponding BitState, which is faster,
+// and converts the states of the result back to States.
+func Search(ctx context.Context, s *State, findMin bool, opt game.Options) (*game.TypedResult[*State], error) {
+	if s.n() != N {
+		return game.SearchOf(ctx, s, findMin, opt)
+	}
+	r, err := game.Search(ctx, s.BitState(), findMin, opt)
+	if r.Next != nil {
+		r.Next = r.Next.(*BitState).State()
+	}
+	for i, u := range r.PV {
+		r.PV[i] = u.(*BitState).State()
+	}
+	return game.ResultOf[*State](r), err
+}
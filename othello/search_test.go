@@ -0,0 +1,179 @@
+package othello
+
+import (
+	"context"
+	"github.com/z-rui/game"
+	"testing"
+	"time"
+)
+
+func TestSearchWithTable(t *testing.T) {
+	s := NewState()
+	for depth := uint(1); depth <= 5; depth++ {
+		_, want := game.MinMax(s, depth, false)
+		opt := game.Options{MaxDepth: depth, Table: game.NewTable(16)}
+		r, _ := game.Search(context.Background(), s, false, opt)
+		if got := r.Eval; got != want {
+			t.Errorf("depth %d: got %v, want %v", depth, got, want)
+		}
+	}
+}
+
+func TestSearchCancel(t *testing.T) {
+	s := NewState()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	r, err := game.Search(ctx, s, false, game.Options{})
+	if err != context.DeadlineExceeded {
+		t.Errorf("search not cancelled: %v", err)
+	}
+	if r.Next == nil {
+		t.Errorf("no move found before cancellation")
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	r, err = game.Search(ctx, s, false, game.Options{})
+	if err != context.Canceled || r.Next != nil {
+		t.Errorf("cancelled search returned %v, %v", r.Next, err)
+	}
+}
+
+// midgame returns a fixed position after the given number of moves.
+func midgame(moves int) *State {
+	s := NewState()
+	for k := 0; k < moves; k++ {
+		nxt := s.Next()
+		if len(nxt) == 0 {
+			break
+		}
+		s = nxt[(k*7)%len(nxt)].(*State)
+	}
+	return s
+}
+
+func TestSearchBitState(t *testing.T) {
+	for _, s := range []*State{midgame(10), midgame(30), NewStateSize(6)} {
+		findMin := s.Turn == X
+		opt := game.Options{MaxDepth: 5, Table: game.NewTable(16), PVS: true}
+		want, _ := game.Search(context.Background(), s, findMin, opt)
+		opt.Table.Clear()
+		r, err := Search(context.Background(), s, findMin, opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Eval != want.Eval || r.Nodes != want.Nodes || len(r.PV) != len(want.PV) {
+			t.Fatalf("got %v in %d nodes, want %v in %d nodes",
+				r.Eval, r.Nodes, want.Eval, want.Nodes)
+		}
+		for i, u := range r.PV {
+			if w := want.PV[i].(*State); !sameBoard(u.Board, w.Board) || u.LastMove != w.LastMove {
+				t.Errorf("PV differs at %d: got %v, want %v", i, u.LastMove, w.LastMove)
+			}
+		}
+	}
+}
+
+func TestParallelSearch(t *testing.T) {
+	for _, moves := range []int{10, 20, 30} {
+		s := midgame(moves)
+		findMin := s.Turn == X
+		serial, _ := game.Search(context.Background(), s, findMin,
+			game.Options{MaxDepth: 5, Table: game.NewTable(16)})
+		for _, workers := range []int{2, 4} {
+			r, _ := game.Search(context.Background(), s, findMin,
+				game.Options{MaxDepth: 5, Table: game.NewTable(16), Workers: workers})
+			if r.Eval != serial.Eval {
+				t.Errorf("after %d moves, %d workers: got %v, want %v",
+					moves, workers, r.Eval, serial.Eval)
+			}
+		}
+		_, want := game.MinMax(s, 5, findMin)
+		if serial.Eval != want {
+			t.Errorf("after %d moves: got %v, want %v", moves, serial.Eval, want)
+		}
+	}
+}
+
+func TestPVS(t *testing.T) {
+	for _, moves := range []int{10, 20, 30} {
+		s := midgame(moves)
+		findMin := s.Turn == X
+		_, want := game.MinMax(s, 5, findMin)
+		for _, opt := range []game.Options{
+			{MaxDepth: 5, PVS: true},
+			{MaxDepth: 5, PVS: true, Table: game.NewTable(16)},
+			{MaxDepth: 5, PVS: true, Table: game.NewTable(16), Aspiration: 10},
+			{MaxDepth: 5, Table: game.NewTable(16), Aspiration: 10},
+		} {
+			r, _ := game.Search(context.Background(), s, findMin, opt)
+			if r.Eval != want {
+				t.Errorf("after %d moves, %+v: got %v, want %v",
+					moves, opt, r.Eval, want)
+			}
+		}
+	}
+}
+
+// benchmarkSearch searches midgame positions,
+// and reports the number of nodes visited per search.
+func benchmarkSearch(b *testing.B, opt game.Options) {
+	var nodes uint64
+	for i := 0; i < b.N; i++ {
+		for _, moves := range []int{10, 20, 30} {
+			s := midgame(moves)
+			if opt.Table != nil {
+				opt.Table.Clear()
+			}
+			r, _ := game.Search(context.Background(), s, s.Turn == X, opt)
+			nodes += r.Nodes
+		}
+	}
+	b.ReportMetric(float64(nodes)/float64(b.N), "nodes/op")
+}
+
+func BenchmarkMinMax(b *testing.B) {
+	benchmarkSearch(b, game.Options{MaxDepth: 6})
+}
+
+func BenchmarkMinMaxTable(b *testing.B) {
+	benchmarkSearch(b, game.Options{MaxDepth: 6, Table: game.NewTable(18)})
+}
+
+func BenchmarkPVS(b *testing.B) {
+	benchmarkSearch(b, game.Options{MaxDepth: 6, PVS: true})
+}
+
+func BenchmarkPVSTable(b *testing.B) {
+	benchmarkSearch(b, game.Options{MaxDepth: 6, PVS: true, Table: game.NewTable(18)})
+}
+
+func BenchmarkPVSAspiration(b *testing.B) {
+	benchmarkSearch(b, game.Options{MaxDepth: 6, PVS: true, Table: game.NewTable(18), Aspiration: 10})
+}
+
+func BenchmarkPVSOrdering(b *testing.B) {
+	benchmarkSearch(b, game.Options{MaxDepth: 6, PVS: true, Table: game.NewTable(18), Killers: true, History: true})
+}
+
+func TestOrdering(t *testing.T) {
+	for _, moves := range []int{10, 20, 30} {
+		s := midgame(moves)
+		findMin := s.Turn == X
+		for _, pvs := range []bool{false, true} {
+			opt := game.Options{MaxDepth: 6, PVS: pvs, Table: game.NewTable(18)}
+			plain, _ := game.Search(context.Background(), s, findMin, opt)
+			opt.Table = game.NewTable(18)
+			opt.Killers, opt.History = true, true
+			ordered, _ := game.Search(context.Background(), s, findMin, opt)
+			if ordered.Eval != plain.Eval {
+				t.Errorf("after %d moves, pvs %v: got %v, want %v",
+					moves, pvs, ordered.Eval, plain.Eval)
+			}
+			if ordered.Nodes >= plain.Nodes {
+				t.Errorf("after %d moves, pvs %v: %d nodes with ordering, %d without",
+					moves, pvs, ordered.Nodes, plain.Nodes)
+			}
+		}
+	}
+}
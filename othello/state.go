@@ -1,10 +1,20 @@
 package othello
 
-import "github.com/z-rui/game"
+import (
+	"github.com/z-rui/game"
+	"strconv"
+)
 
-// N is the board size of the Othello game.
+// N is the standard board size of the Othello game.
 const N = 8
 
+// MinN and MaxN are the least and the greatest board sizes supported.
+// The board size must be even.
+const (
+	MinN = 4
+	MaxN = 16
+)
+
 // Cell represents a cell of the board.
 // It has three states: Empty, O and X.
 type Cell uint8
@@ -28,37 +38,76 @@ func (c Cell) String() string {
 }
 
 // State represents the current state of the game.
+// Board must have Size*Size cells, or N*N if Size is zero.
+// A State may be built directly from its Board and Turn;
+// the discs are then counted when needed.
 type State struct {
-	Board          [N][N]Cell
-	countO, countX uint8
+	Board          []Cell // cells in row-major order
+	Size           int    // board size; zero means N
+	countO, countX int    // disc counts, if counted
+	counted        bool
 	LastMove       Move
-	Turn           Cell // must be O or X
+	Turn           Cell       // must be O or X
+	history        []snapshot // states before the moves played by Play
+	boards         []Cell     // boards before the moves played by Play
 }
 
-// NewState returns a new state at the start of the game.
+// NewState returns a new state at the start of the game,
+// on the standard board.
 func NewState() *State {
-	s := new(State)
-	const (
-		x = N/2 - 1
-		y = x + 1
-	)
-	s.Board[x][x] = O
-	s.Board[x][y] = X
-	s.Board[y][x] = X
-	s.Board[y][y] = O
+	return NewStateSize(N)
+}
+
+// NewStateSize returns a new state at the start of the game,
+// on the board of the given size, which must be even and
+// between MinN and MaxN.
+func NewStateSize(n int) *State {
+	if n%2 != 0 || n < MinN || n > MaxN {
+		panic("othello: bad board size " + strconv.Itoa(n))
+	}
+	s := &State{Board: make([]Cell, n*n), Size: n}
+	x, y := n/2-1, n/2
+	s.Board[x*n+x] = O
+	s.Board[x*n+y] = X
+	s.Board[y*n+x] = X
+	s.Board[y*n+y] = O
 	s.countO = 2
 	s.countX = 2
+	s.counted = true
 	s.LastMove = invalidMove
 	s.Turn = O
 	return s
 }
 
+// n returns the board size.
+func (s *State) n() int {
+	n := s.Size
+	if n == 0 {
+		n = N
+	}
+	if len(s.Board) != n*n {
+		badBoard(len(s.Board), n)
+	}
+	return n
+}
+
+// badBoard panics on a board of k cells for size n.
+func badBoard(k, n int) {
+	panic("othello: " + strconv.Itoa(k) + " cells on a board of size " + strconv.Itoa(n))
+}
+
+// contains tells if m is a position on the board.
+func (s *State) contains(m Move) bool {
+	return int(m.I) < s.n() && int(m.J) < s.n()
+}
+
 // clone clones a state.
 func (s *State) clone() *State {
 	t := new(State)
-	t.Board = s.Board
-	t.countO = s.countO
-	t.countX = s.countX
+	t.Board = append([]Cell(nil), s.Board...)
+	t.Size = s.Size
+	t.countO, t.countX = s.Count()
+	t.counted = true
 	t.Turn = s.Turn
 	return t
 }
@@ -72,105 +121,132 @@ func (s *State) Pass() *State {
 }
 
 // Count returns the counts of O's and X's on the board.
-func (s *State) Count() (o uint8, x uint8) {
-	o = s.countO
-	x = s.countX
+func (s *State) Count() (o int, x int) {
+	if s.counted {
+		return s.countO, s.countX
+	}
+	// the board was set directly
+	for _, c := range s.Board {
+		switch c {
+		case O:
+			o++
+		case X:
+			x++
+		}
+	}
 	return
 }
 
 // Dim returns the dimension of the board
 func (s *State) Dim() (rows int, cols int) {
-	rows = N
-	cols = N
+	rows = s.n()
+	cols = s.n()
 	return
 }
 
 // Get returns the string representation at (i, j)
 func (s *State) Get(i, j int) string {
-	return s.Board[i][j].String()
+	return s.Board[i*s.n()+j].String()
 }
 
-// valueMap assigns a value to each cell of the board
-var valueMap [N][N]int8
+// valueMaps assigns a value to each cell of the board of each size.
+var valueMaps [MaxN + 1][MaxN][MaxN]int8
 
-// valueMap generation
+// cornerValues are the values of the cells near a corner,
+// by the distances from the two nearest edges, the smaller first.
+// Cells farther than 3 from both edges are valued as if they
+// were 3 from the edges.
+var cornerValues = [4][4]int8{
+	{99, -8, 8, 6},
+	{0, -24, -4, -3},
+	{0, 0, 7, 4},
+	{0, 0, 0, 0},
+}
+
+// valueMaps generation
 func init() {
-	i, j := 0, 0
-	for _, v := range [...]int8{
-		/*1:*/ 99, -8, 8, 6,
-		/*2: */ -24, -4, -3,
-		/*3:        */ 7, 4,
-		/*4:           */ 0,
-	} {
-		valueMap[i][j] = v
-		valueMap[i][N-j-1] = v
-		valueMap[j][i] = v
-		valueMap[j][N-i-1] = v
-		valueMap[N-i-1][j] = v
-		valueMap[N-i-1][N-j-1] = v
-		valueMap[N-j-1][i] = v
-		valueMap[N-j-1][N-i-1] = v
-		j++
-		if j == N/2 {
-			i++
-			j = i
+	for n := MinN; n <= MaxN; n += 2 {
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				a, b := edgeDistance(i, n), edgeDistance(j, n)
+				if a > b {
+					a, b = b, a
+				}
+				valueMaps[n][i][j] = cornerValues[a][b]
+			}
 		}
 	}
 }
 
+// edgeDistance returns the distance of row (or column) i
+// from the nearest edge of the board of size n, at most 3.
+func edgeDistance(i, n int) int {
+	if i > n-1-i {
+		i = n - 1 - i
+	}
+	if i > 3 {
+		i = 3
+	}
+	return i
+}
+
 // Eval returns the evaluation of the current state.
 func (s *State) Eval() (eval game.Evaluation) {
 	if s.IsEnd() {
-		switch {
-		case s.countO > s.countX:
+		switch o, x := s.Count(); {
+		case o > x:
 			eval = game.Won
-		case s.countO < s.countX:
+		case o < x:
 			eval = game.Lost
 		}
 	} else {
-		for i := 0; i < N; i++ {
-			for j := 0; j < N; j++ {
-				switch s.Board[i][j] {
-				case O:
-					eval += game.Evaluation(valueMap[i][j])
-				case X:
-					eval -= game.Evaluation(valueMap[i][j])
-				}
+		n := s.n()
+		valueMap := &valueMaps[n]
+		for k, c := range s.Board {
+			switch c {
+			case O:
+				eval += game.Evaluation(valueMap[k/n][k%n])
+			case X:
+				eval -= game.Evaluation(valueMap[k/n][k%n])
 			}
 		}
 	}
 	return
 }
 
-// IsEnd tells if the game has ended.
+// IsEnd tells if the game has ended,
+// i.e. neither player can move.
 func (s *State) IsEnd() bool {
-	// BUG() IsEnd won't return true if the state
-	// is generated by passing twice.
-	return s.countO == 0 || s.countX == 0 || s.countO+s.countX == N*N
+	if o, x := s.Count(); o == 0 || x == 0 || o+x == len(s.Board) {
+		return true
+	}
+	return !s.canMove(s.Turn) && !s.canMove(s.Turn^(O^X))
 }
 
 // MustPass tells if the current user must pass.
 func (s *State) MustPass() bool {
-	var i, j uint8
-	for i = 0; i < N; i++ {
-		for j = 0; j < N; j++ {
-			if (Move{i, j}).Allowed(s) {
-				return false
-			}
+	return !s.canMove(s.Turn)
+}
+
+// canMove tells if player p has an allowed move.
+func (s *State) canMove(p Cell) bool {
+	for _, mv := range validMoves[s.n()] {
+		if s.allowed(p, mv) {
+			return true
 		}
 	}
-	return true
+	return false
 }
 
 // Next returns all possible next states.
 func (s *State) Next() (nxt []game.State) {
 	nxt = make([]game.State, 0, 4)
-	for _, mv := range validMoves {
+	for _, mv := range validMoves[s.n()] {
 		if t := s.Move(mv); t != nil {
 			nxt = append(nxt, t)
 		}
 	}
-	if len(nxt) == 0 && !s.IsEnd() && s.LastMove != invalidMove {
+	if len(nxt) == 0 && !s.IsEnd() {
 		nxt = append(nxt, s.Pass())
 	}
 	return
@@ -180,7 +256,7 @@ func (s *State) Next() (nxt []game.State) {
 // It returns nil if the move is not allowed.
 func (s *State) Move(m Move) (t *State) {
 	i, j := int(m.I), int(m.J)
-	if !m.Valid() || s.Board[i][j] != Empty {
+	if !s.contains(m) || s.Board[i*s.n()+j] != Empty {
 		return nil
 	}
 	for di := -1; di <= 1; di++ {
@@ -188,12 +264,12 @@ func (s *State) Move(m Move) (t *State) {
 			if di == 0 && dj == 0 {
 				continue
 			}
-			n := s.match(i, j, di, dj)
+			n := s.match(s.Turn, i, j, di, dj)
 			if n > 0 {
-				// CoW 8x8 board can save time
+				// CoW board can save time
 				if t == nil {
 					t = s.clone()
-					t.Board[i][j] = s.Turn
+					t.Board[i*s.n()+j] = s.Turn
 				}
 				t.flip(i, j, di, dj, n)
 			}
@@ -211,19 +287,20 @@ func (s *State) Move(m Move) (t *State) {
 	return
 }
 
-// match finds how many discs will be reversed in the given direction.
-func (s *State) match(i, j, di, dj int) int {
-	n := 0
+// match finds how many discs will be reversed in the given direction,
+// if player p places a disc at (i, j).
+func (s *State) match(p Cell, i, j, di, dj int) int {
+	n, size := 0, s.n()
 	for {
 		i += di
 		j += dj
-		if !(0 <= i && i < N && 0 <= j && j < N) {
+		if !(0 <= i && i < size && 0 <= j && j < size) {
 			return 0
 		}
-		switch s.Board[i][j] {
+		switch s.Board[i*size+j] {
 		case Empty:
 			return 0
-		case s.Turn:
+		case p:
 			return n
 		}
 		n++
@@ -232,17 +309,18 @@ func (s *State) match(i, j, di, dj int) int {
 
 // flip flips the given amount of discs in the given direction.
 func (s *State) flip(i, j, di, dj, n int) {
-	if s.Turn == O {
-		s.countO += uint8(n)
-		s.countX -= uint8(n)
-	} else {
-		s.countO -= uint8(n)
-		s.countX += uint8(n)
-	}
+	size, k := s.n(), n
 	for n > 0 {
 		i += di
 		j += dj
-		s.Board[i][j] = s.Turn
+		s.Board[i*size+j] = s.Turn
 		n--
 	}
+	if s.Turn == O {
+		s.countO += k
+		s.countX -= k
+	} else {
+		s.countO -= k
+		s.countX += k
+	}
 }
@@ -1,12 +1,39 @@
 package othello
 
-import "testing"
+import (
+	"context"
+	"github.com/z-rui/game"
+	"testing"
+	"time"
+)
 
 const E = Empty
 
+// board returns the cells of the rows in row-major order.
+func board(rows [N][N]Cell) []Cell {
+	b := make([]Cell, 0, N*N)
+	for i := range rows {
+		b = append(b, rows[i][:]...)
+	}
+	return b
+}
+
+// sameBoard tells if the two boards have the same cells.
+func sameBoard(a, b []Cell) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if a[k] != b[k] {
+			return false
+		}
+	}
+	return true
+}
+
 func TestHasValidMoves(t *testing.T) {
 	s := State{
-		Board: [N][N]Cell{
+		Board: board([N][N]Cell{
 			{O, O, O, O, O, O, O, O},
 			{O, O, X, X, X, X, X, X},
 			{O, O, O, O, O, O, X, X},
@@ -15,14 +42,14 @@ func TestHasValidMoves(t *testing.T) {
 			{O, O, O, O, O, O, X, X},
 			{O, O, O, O, O, O, O, X},
 			{O, X, O, E, O, O, E, O},
-		},
+		}),
 		LastMove: Move{6, 7},
 		Turn:     O,
 	}
 	if !s.MustPass() {
 		t.Errorf("This should not have valid moves: %v", s)
 	}
-	s.Board = [N][N]Cell{
+	s.Board = board([N][N]Cell{
 		{E, O, O, O, O, E, E},
 		{E, E, O, O, O, O, E},
 		{O, O, O, O, O, O, O},
@@ -31,7 +58,7 @@ func TestHasValidMoves(t *testing.T) {
 		{X, X, X, X, O, O, O},
 		{X, X, X, O, O, O, E},
 		{X, X, O, E, E, E, O},
-	}
+	})
 	s.LastMove = Move{7, 2}
 	s.Turn = O
 	if s.MustPass() {
@@ -41,13 +68,13 @@ func TestHasValidMoves(t *testing.T) {
 
 func TestFlip(t *testing.T) {
 	s := NewState()
-	n := s.match(2, 4, 1, 0)
+	n := s.match(s.Turn, 2, 4, 1, 0)
 	if n != 1 {
 		t.Errorf("Should match 1, got %d", n)
 	}
-	s.Board[2][4] = O
+	s.Board[2*N+4] = O
 	s.flip(2, 4, 1, 0, n)
-	reference := [N][N]Cell{
+	reference := board([N][N]Cell{
 		{E, E, E, E, E, E, E, E},
 		{E, E, E, E, E, E, E, E},
 		{E, E, E, E, O, E, E, E},
@@ -56,8 +83,8 @@ func TestFlip(t *testing.T) {
 		{E, E, E, E, E, E, E, E},
 		{E, E, E, E, E, E, E, E},
 		{E, E, E, E, E, E, E, E},
-	}
-	if s.Board != reference {
+	})
+	if !sameBoard(s.Board, reference) {
 		t.Errorf("Board mismatch")
 	}
 }
@@ -65,7 +92,7 @@ func TestFlip(t *testing.T) {
 func TestMove(t *testing.T) {
 	s := NewState()
 	s = s.Move(Move{2, 4})
-	reference := [N][N]Cell{
+	reference := board([N][N]Cell{
 		{E, E, E, E, E, E, E, E},
 		{E, E, E, E, E, E, E, E},
 		{E, E, E, E, O, E, E, E},
@@ -74,8 +101,8 @@ func TestMove(t *testing.T) {
 		{E, E, E, E, E, E, E, E},
 		{E, E, E, E, E, E, E, E},
 		{E, E, E, E, E, E, E, E},
-	}
-	if s.Board != reference {
+	})
+	if !sameBoard(s.Board, reference) {
 		t.Errorf("Board mismatch")
 	}
 }
@@ -91,7 +118,229 @@ func TestValueMap(t *testing.T) {
 		{-8, -24, -4, -3, -3, -4, -24, -8},
 		{99, -8, 8, 6, 6, 8, -8, 99},
 	}
-	if valueMap != reference {
-		t.Errorf("wrong valueMap: %v", valueMap)
+	for i := range reference {
+		for j := range reference[i] {
+			if valueMaps[N][i][j] != reference[i][j] {
+				t.Fatalf("wrong valueMap: %v", valueMaps[N])
+			}
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	s1 := NewState().Move(Move{2, 4}).Move(Move{2, 5}).Move(Move{3, 5})
+	s2 := NewState().Move(Move{3, 5}).Move(Move{2, 5}).Move(Move{2, 4})
+	if !sameBoard(s1.Board, s2.Board) {
+		t.Fatalf("Board mismatch")
+	}
+	if s1.Hash() != s2.Hash() {
+		t.Errorf("transposed states hashed differently")
+	}
+	if s1.Hash() == s1.Pass().Hash() {
+		t.Errorf("passing does not change the hash")
+	}
+}
+
+// perfect returns the final disc differential by brute force,
+// assuming both players play perfectly.
+func perfect(s *State) int {
+	nxt := s.Next()
+	if len(nxt) == 0 {
+		o, x := s.Count()
+		return o - x
+	}
+	best := 0
+	for i, t := range nxt {
+		d := perfect(t.(*State))
+		if i == 0 || s.Turn == O && d > best || s.Turn == X && d < best {
+			best = d
+		}
+	}
+	return best
+}
+
+func TestSolve(t *testing.T) {
+	// the table is shared with a search by the usual evaluation
+	table := game.NewTable(16)
+	opt := game.Options{Table: table, PVS: true, Killers: true, History: true}
+	for _, moves := range []int{50, 52, 54} {
+		s := midgame(moves)
+		want := perfect(s)
+		game.Search(context.Background(), s, s.Turn == X, game.Options{MaxDepth: 4, Table: table})
+		diff, line, err := Solve(context.Background(), s, opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff != want {
+			t.Errorf("after %d moves: got %d, want %d", moves, diff, want)
+		}
+		if len(line) == 0 {
+			t.Fatalf("after %d moves: no line", moves)
+		}
+		last := line[len(line)-1]
+		if o, x := last.Count(); len(last.Next()) != 0 || o-x != diff {
+			t.Errorf("after %d moves: line does not end with %d: %v", moves, diff, last)
+		}
+	}
+}
+
+func TestSolveCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := Solve(ctx, midgame(40), game.Options{}); err != context.Canceled {
+		t.Errorf("cancelled solve returned %v", err)
+	}
+	_, _, err := Solve(context.Background(), midgame(20), game.Options{Budget: time.Millisecond})
+	if err != context.DeadlineExceeded {
+		t.Errorf("solve out of budget returned %v", err)
+	}
+}
+
+func TestBlocked(t *testing.T) {
+	s := State{Board: make([]Cell, N*N)}
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			switch {
+			case j < 3:
+				s.Board[i*N+j] = O
+			case j > 3:
+				s.Board[i*N+j] = X
+			}
+		}
+	}
+	s.LastMove = Move{0, 4}
+	for _, turn := range []Cell{O, X} {
+		s.Turn = turn
+		if !s.IsEnd() {
+			t.Errorf("%v to move: blocked board not ended", turn)
+		}
+		if nxt := s.Next(); len(nxt) != 0 {
+			t.Errorf("%v to move: blocked board has next states: %v", turn, nxt)
+		}
+		if e := s.Eval(); e != game.Lost {
+			t.Errorf("%v to move: lost game not evaluated Lost: %v", turn, e)
+		}
+		b := s.BitState()
+		if !b.IsEnd() || len(b.Next()) != 0 || b.Eval() != game.Lost {
+			t.Errorf("%v to move: blocked bitboard not ended", turn)
+		}
+	}
+
+	// now O can flank the X's from the empty column
+	for i := 0; i < N; i++ {
+		s.Board[i*N+N-1] = O
+	}
+	s.Turn = X
+	if s.IsEnd() {
+		t.Errorf("game ended while O can move")
+	}
+	nxt := s.Next()
+	if len(nxt) != 1 || nxt[0].(*State).LastMove != invalidMove {
+		t.Errorf("X does not pass: %v", nxt)
+	}
+	nxt = s.BitState().Next()
+	if len(nxt) != 1 || nxt[0].(*BitState).LastMove != invalidMove {
+		t.Errorf("X does not pass on bitboard: %v", nxt)
+	}
+}
+
+func TestBuilt(t *testing.T) {
+	start := NewState()
+	s := &State{Board: append([]Cell(nil), start.Board...), Turn: O}
+	if o, x := s.Count(); o != 2 || x != 2 || s.Empties() != N*N-4 {
+		t.Errorf("start position built directly: count %d, %d", o, x)
+	}
+	if s.IsEnd() || s.MustPass() || s.Eval() != start.Eval() {
+		t.Errorf("start position built directly has ended")
+	}
+	if nxt := s.Next(); len(nxt) != 4 {
+		t.Errorf("start position built directly: %d next states", len(nxt))
+	}
+	for depth := uint(1); depth <= 5; depth++ {
+		want := game.Perft(start, depth)
+		if got := game.Perft(s, depth); got != want {
+			t.Errorf("depth %d: got %d, want %d", depth, got, want)
+		}
+		if got := game.Perft(struct{ game.State }{s}, depth); got != want {
+			t.Errorf("depth %d without Mover: got %d, want %d", depth, got, want)
+		}
+	}
+	if b := s.BitState(); !sameState(s, b) || b.IsEnd() || b.Eval() != s.Eval() {
+		t.Errorf("start position built directly differs from its bitboard")
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	for k := Identity; k < Transforms; k++ {
+		for _, m := range validMoves[N] {
+			if got := k.Inverse().Move(k.Move(m, N), N); got != m {
+				t.Errorf("transform %d: %v maps back to %v", k, m, got)
+			}
+		}
+	}
+	s := midgame(20)
+	want, _ := s.Canonical()
+	for k := Identity; k < Transforms; k++ {
+		u := s.Transform(k)
+		if u.Eval() != s.Eval() || game.Perft(u, 3) != game.Perft(s, 3) {
+			t.Errorf("transform %d changed the position", k)
+		}
+		c, ct := u.Canonical()
+		if !sameBoard(c.Board, want.Board) {
+			t.Errorf("transform %d: different canonical board", k)
+		}
+		if back := c.Transform(ct.Inverse()); !sameBoard(back.Board, u.Board) || back.LastMove != u.LastMove {
+			t.Errorf("transform %d: canonical transform does not map back", k)
+		}
+	}
+}
+
+func TestSize(t *testing.T) {
+	// the board edges make no difference in the first plies
+	for depth := uint(1); depth <= 5; depth++ {
+		want := game.Perft(NewStateSize(MaxN), depth)
+		if depth < 4 && want != perftTable[depth] {
+			t.Errorf("size %d, depth %d: got %d, want %d", MaxN, depth, want, perftTable[depth])
+		}
+		if got := game.Perft(NewStateSize(12), depth); got != want {
+			t.Errorf("size 12, depth %d: got %d, want %d", depth, got, want)
+		}
+	}
+	for n := MinN; n <= MaxN; n += 2 {
+		s := NewStateSize(n)
+		if o, x := s.Count(); o != 2 || x != 2 || s.Empties() != n*n-4 {
+			t.Errorf("size %d: bad initial board", n)
+		}
+		if rows, cols := s.Dim(); rows != n || cols != n {
+			t.Errorf("size %d: got dimension %dx%d", n, rows, cols)
+		}
+		if got, want := game.Perft(s, 4), game.Perft(noMover{s}, 4); got != want {
+			t.Errorf("size %d: mover perft got %d, want %d", n, got, want)
+		}
+	}
+
+	// the whole game on the smallest board
+	s := NewStateSize(MinN)
+	if diff, _, _ := Solve(context.Background(), s, game.Options{}); diff != perfect(s) {
+		t.Errorf("size %d: solved %d, want %d", MinN, diff, perfect(s))
+	}
+	if v := valueMaps[6]; v[5][5] != 99 || v[4][4] != -24 || v[2][3] != 7 {
+		t.Errorf("wrong valueMap for size 6: %v", v)
+	}
+}
+
+func TestBadSize(t *testing.T) {
+	for _, s := range []*State{
+		{Board: make([]Cell, 6*6)},
+		{Board: make([]Cell, N*N), Size: 6},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%d cells with size %d: no panic", len(s.Board), s.Size)
+				}
+			}()
+			s.Next()
+		}()
 	}
 }
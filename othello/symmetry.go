@@ -0,0 +1,85 @@
+package othello
+
+// Transform is one of the 8 symmetries of the board:
+// it reflects the rows if the bit 1 is set, the columns if the bit 2
+// is set, and then transposes the board if the bit 4 is set.
+type Transform uint8
+
+// Identity is the transform that changes nothing.
+const Identity Transform = 0
+
+// Transforms is the number of transforms.
+const Transforms = 8
+
+// Move maps m through the transform on the board of size n.
+// A pass stays a pass.
+func (t Transform) Move(m Move, n int) Move {
+	if !m.Valid() {
+		return m
+	}
+	last := uint8(n - 1)
+	if t&1 != 0 {
+		m.I = last - m.I
+	}
+	if t&2 != 0 {
+		m.J = last - m.J
+	}
+	if t&4 != 0 {
+		m.I, m.J = m.J, m.I
+	}
+	return m
+}
+
+// Inverse returns the transform that undoes t.
+func (t Transform) Inverse() Transform {
+	if t&4 == 0 {
+		return t
+	}
+	// the reflections swap places across the transposition
+	return 4 | t&1<<1 | t&2>>1
+}
+
+// Transform returns the state with the board and the last move
+// mapped through t.
+func (s *State) Transform(t Transform) *State {
+	n := s.n()
+	u := &State{
+		Board:    make([]Cell, n*n),
+		Size:     s.Size,
+		counted:  true,
+		LastMove: t.Move(s.LastMove, n),
+		Turn:     s.Turn,
+	}
+	u.countO, u.countX = s.Count()
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			m := t.Move(Move{uint8(i), uint8(j)}, n)
+			u.Board[int(m.I)*n+int(m.J)] = s.Board[i*n+j]
+		}
+	}
+	return u
+}
+
+// Canonical returns the representative of the states symmetric to s,
+// which is the same for all of them, and the transform that maps s
+// to it.  The representative has the least board in the row-major
+// order; the last move is not considered.
+func (s *State) Canonical() (c *State, t Transform) {
+	c, t = s.Transform(Identity), Identity
+	for k := Identity + 1; k < Transforms; k++ {
+		if u := s.Transform(k); less(u.Board, c.Board) {
+			c, t = u, k
+		}
+	}
+	return
+}
+
+// less compares two boards in the row-major order.
+func less(a, b []Cell) bool {
+	for k := range a {
+		if a[k] != b[k] {
+			return a[k] < b[k]
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package game
+
+import "sync"
+
+// split searches s with the given number of workers.
+//
+// The first next state is searched alone, to get a bound for the
+// others, which are then divided among the workers ("young brothers
+// wait" at the root).  Workers share the transposition table, and
+// every next state is searched with the best evaluation found so far.
+func (sr *searcher) split(s State, iterations uint, findMin bool, workers int) (next State, eval Evaluation) {
+	nxt := s.Next()
+	if iterations == 0 || len(nxt) <= 1 {
+		return sr.minmax(s, iterations, findMin, Lost, Won)
+	}
+	sr.begin(iterations)
+	if sr.stop() {
+		return
+	}
+	hash, hashed := sr.hash(s)
+	first := -1
+	if hashed {
+		var done bool
+		if first, eval, done = sr.probe(hash, len(nxt), iterations, Lost, Won); done {
+			next = nxt[first]
+			sr.pv[0] = append(sr.pv[0][:0], next)
+			return
+		}
+	}
+
+	perm := sr.arrange(nxt, 0, first, findMin)
+
+	// child searches t with the window bounded by eval.
+	child := func(w *searcher, t State, eval Evaluation) Evaluation {
+		if findMin {
+			_, e := w.max(t, iterations-1, Lost, sooner(eval))
+			return e.Later()
+		}
+		_, e := w.min(t, iterations-1, sooner(eval), Won)
+		return e.Later()
+	}
+	better := func(e, eval Evaluation) bool {
+		if findMin {
+			return e < eval
+		}
+		return e > eval
+	}
+
+	eval = Lost
+	if findMin {
+		eval = Won
+	}
+	e := child(sr, nxt[0], eval)
+	if sr.aborted {
+		return
+	}
+	next, eval = nxt[0], e
+	sr.setPV(0, next)
+	best := 0
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		jobs    = make(chan int)
+		helpers = make([]searcher, workers)
+	)
+	for k := range helpers {
+		w := &helpers[k]
+		w.ctx, w.table, w.deadline = sr.ctx, sr.table, sr.deadline
+		if sr.killers != nil {
+			w.killers = [][killerSlots]int{}
+		}
+		if sr.history != nil {
+			w.history = new([2][]uint64)
+		}
+		w.begin(iterations)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				mu.Lock()
+				bound := eval
+				mu.Unlock()
+				e := child(w, nxt[i], bound)
+				if w.aborted {
+					continue
+				}
+				mu.Lock()
+				if better(e, eval) {
+					next, eval, best = nxt[i], e, i
+					w.setPV(0, next)
+					sr.pv[0] = append(sr.pv[0][:0], w.pv[0]...)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := 1; i < len(nxt); i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for k := range helpers {
+		w := &helpers[k]
+		sr.nodes += w.nodes
+		sr.cutoffs += w.cutoffs
+		sr.aborted = sr.aborted || w.aborted
+		sr.deeper = sr.deeper || w.deeper
+	}
+	if hashed && !sr.aborted {
+		sr.record(hash, iterations, !sr.deeper, eval, Exact, best, perm)
+	}
+	return
+}
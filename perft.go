@@ -0,0 +1,46 @@
+package game
+
+// Perft counts the states reached from s in exactly depth plies,
+// i.e. the leaves of the game tree of the given depth;
+// games ending earlier are not counted.
+//
+// It verifies the generation of next states, by comparing
+// the counts with known references.
+//
+// If s implements Mover, the moves are played in place instead.
+func Perft(s State, depth uint) uint64 {
+	if m, ok := s.(Mover); ok {
+		return perftMove(m.Clone(), depth, make([][]int, depth))
+	}
+	if depth == 0 {
+		return 1
+	}
+	nxt := s.Next()
+	if depth == 1 {
+		return uint64(len(nxt))
+	}
+	var n uint64
+	for _, t := range nxt {
+		n += Perft(t, depth-1)
+	}
+	return n
+}
+
+// perftMove is Perft on a Mover, with a buffer of moves for each depth.
+func perftMove(m Mover, depth uint, buf [][]int) uint64 {
+	if depth == 0 {
+		return 1
+	}
+	moves := m.Moves(buf[depth-1][:0])
+	buf[depth-1] = moves
+	if depth == 1 {
+		return uint64(len(moves))
+	}
+	var n uint64
+	for _, mv := range moves {
+		m.Play(mv)
+		n += perftMove(m, depth-1, buf)
+		m.Undo()
+	}
+	return n
+}
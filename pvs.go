@@ -0,0 +1,113 @@
+package game
+
+// negamax runs the principal variation search on s within (α, β).
+// The result is the evaluation in the usual sense, as that of minmax.
+func (sr *searcher) negamax(s State, iterations uint, findMin bool, α, β Evaluation) (next State, eval Evaluation) {
+	sr.begin(iterations)
+	if findMin {
+		next, eval = sr.pvs(s, iterations, -β, -α, -1)
+		return next, -eval
+	}
+	return sr.pvs(s, iterations, α, β, 1)
+}
+
+// pvs is the principal variation search (NegaScout) in negamax form:
+// evaluations are seen from the player to move, whose sign is 1
+// if the player maximises, and -1 otherwise.
+//
+// The first next state is searched with the window (α, β); the others
+// only with a null window to prove that they are not better.
+// Those which turn out to be better are searched again.
+func (sr *searcher) pvs(s State, iterations uint, α, β Evaluation, sign int) (next State, eval Evaluation) {
+	if sr.stop() {
+		return
+	}
+	ply := sr.depth - iterations
+	nxt := s.Next()
+	if iterations == 0 || len(nxt) == 0 {
+		if len(nxt) != 0 {
+			sr.deeper = true
+		}
+		sr.pv[ply] = sr.pv[ply][:0]
+		eval = s.Eval()
+		if sign < 0 {
+			eval = -eval
+		}
+		return
+	}
+	hash, hashed := sr.hash(s)
+	first := -1
+	if hashed {
+		// the table keeps evaluations in the usual sense
+		lo, hi := α, β
+		if sign < 0 {
+			lo, hi = -β, -α
+		}
+		var done bool
+		if first, eval, done = sr.probe(hash, len(nxt), iterations, lo, hi); done {
+			next = nxt[first]
+			if sign < 0 {
+				eval = -eval
+			}
+			sr.pv[ply] = append(sr.pv[ply][:0], next)
+			return
+		}
+	}
+	perm := sr.arrange(nxt, ply, first, sign < 0)
+	deeper, α0, best := sr.deeper, α, 0
+	sr.deeper = false
+	eval = Lost
+	for i, t := range nxt {
+		var e Evaluation
+		if i == 0 {
+			_, e = sr.pvs(t, iterations-1, -sooner(β), -sooner(α), -sign)
+			e = (-e).Later()
+		} else {
+			_, e = sr.pvs(t, iterations-1, -sooner(α+1), -sooner(α), -sign)
+			e = (-e).Later()
+			if α < e && e < β && !sr.aborted {
+				_, e = sr.pvs(t, iterations-1, -sooner(β), -sooner(α), -sign)
+				e = (-e).Later()
+			}
+		}
+		if sr.aborted {
+			return
+		}
+		if next == nil || e > eval {
+			next = t
+			eval = e
+			best = i
+			sr.setPV(ply, t)
+			if e > α {
+				α = e
+				if α >= β {
+					sr.reward(t, ply, iterations, sign < 0)
+					sr.cutoffs++
+					break
+				}
+			}
+		}
+	}
+	if hashed {
+		bound := Exact
+		switch {
+		case eval <= α0:
+			bound = UpperBound
+		case eval >= β:
+			bound = LowerBound
+		}
+		e := eval
+		if sign < 0 {
+			e = -e
+			switch bound {
+			case UpperBound:
+				bound = LowerBound
+			case LowerBound:
+				bound = UpperBound
+			}
+		}
+		sr.record(hash, iterations, !sr.deeper, e, bound, best, perm)
+	}
+	sr.deeper = sr.deeper || deeper
+	return
+}
@@ -0,0 +1,205 @@
+package game
+
+import (
+	"context"
+	"time"
+)
+
+// Options controls the behaviour of Search.
+type Options struct {
+	// MaxDepth limits the depth of the search.
+	// Zero means no limit.
+	MaxDepth uint
+	// Budget is the wall-clock time the search may take.
+	// Zero means no limit.
+	Budget time.Duration
+	// Table is the transposition table consulted by the search.
+	// It can be reused across searches.  If nil, no table is used.
+	Table *Table
+	// Workers is the number of goroutines searching in parallel.
+	// The next states of the current state are divided among them.
+	// Zero or one means a serial search, whose result is deterministic.
+	Workers int
+	// PVS selects the principal variation search (NegaScout) instead
+	// of MinMax.  It searches fewer states when the best next state
+	// tends to come first, e.g. when a Table is used.
+	// It is ignored by parallel searches.
+	PVS bool
+	// Aspiration is the half width of the aspiration window:
+	// from the second iteration on, the search assumes the evaluation
+	// is within this distance from that of the previous iteration,
+	// and searches again with the full window if it is not.
+	// Zero means always using the full window.
+	// It is ignored by parallel searches.
+	Aspiration Evaluation
+	// Killers enables the killer move heuristic: a move which caused
+	// a cutoff is tried early in other states at the same ply.
+	// It requires the states to implement MoveKeyer.
+	Killers bool
+	// History enables the history heuristic: moves are tried in the
+	// order of how often, and how deep, they caused cutoffs.
+	// It requires the states to implement MoveKeyer.
+	History bool
+}
+
+// Result is the outcome of Search.
+type Result struct {
+	// Next is the best next state, or nil if there is none.
+	Next State
+	// Eval is the evaluation of Next.
+	Eval Evaluation
+	// PV is the principal variation, the sequence of states
+	// expected if both players play optimally.  It starts with Next.
+	PV []State
+	// Depth is the depth of the deepest completed iteration.
+	Depth uint
+	// Nodes is the number of states visited.
+	Nodes uint64
+	// Cutoffs is the number of α-β cutoffs.
+	Cutoffs uint64
+	// Elapsed is the wall-clock time taken by the search.
+	Elapsed time.Duration
+}
+
+// NPS returns the number of nodes visited per second.
+func (r *Result) NPS() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Nodes) / r.Elapsed.Seconds()
+}
+
+// Search finds an optimal move by iterative deepening:
+// it runs MinMax with depth 1, 2, 3... until either the budget
+// is used up, MaxDepth is reached or the whole game tree is searched.
+//
+// It returns the result of the deepest iteration that completed.
+// The first iteration always completes, regardless of the budget.
+//
+// The search can also be stopped through ctx, in which case
+// it returns the best move found so far, and ctx.Err().
+// If even the first iteration did not complete, the best move
+// is chosen among the next states that have been searched,
+// and the depth is 0.
+//
+// At least one of opt.MaxDepth and opt.Budget should be non-zero,
+// unless the game tree is small enough to be searched completely,
+// or the search is to be stopped through ctx.
+func Search(ctx context.Context, s State, findMin bool, opt Options) (r *Result, err error) {
+	sr := searcher{ctx: ctx, table: opt.Table}
+	if opt.Killers {
+		sr.killers = [][killerSlots]int{}
+	}
+	if opt.History {
+		sr.history = new([2][]uint64)
+	}
+	if m, ok := s.(Mover); ok && opt.Workers <= 1 {
+		sr.root = m.Clone()
+	}
+	r = new(Result)
+	start := time.Now()
+	defer func() {
+		r.Nodes = sr.nodes
+		r.Cutoffs = sr.cutoffs
+		r.Elapsed = time.Since(start)
+	}()
+	for d := uint(1); opt.MaxDepth == 0 || d <= opt.MaxDepth; d++ {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		if d > 1 && opt.Budget > 0 {
+			sr.deadline = start.Add(opt.Budget)
+		}
+		sr.deeper = false
+		var (
+			n State
+			e Evaluation
+		)
+		switch {
+		case opt.Workers > 1:
+			n, e = sr.split(s, d, findMin, opt.Workers)
+		case d > 1 && opt.Aspiration > 0:
+			α, β := window(r.Eval, opt.Aspiration)
+			n, e = sr.serial(s, d, findMin, α, β, opt.PVS)
+			if !sr.aborted && (e <= α && α != Lost || e >= β && β != Won) {
+				// the evaluation is out of the window; search again
+				n, e = sr.serial(s, d, findMin, Lost, Won, opt.PVS)
+			}
+		default:
+			n, e = sr.serial(s, d, findMin, Lost, Won, opt.PVS)
+		}
+		if sr.aborted {
+			if r.Next == nil && n != nil {
+				r.Next, r.Eval = n, e
+				r.PV = append([]State(nil), sr.pv[0]...)
+			}
+			err = ctx.Err()
+			break
+		}
+		r.Next, r.Eval, r.Depth = n, e, d
+		r.PV = sr.extendPV(append(r.PV[:0], sr.pv[0]...), d)
+		if !sr.deeper || e.IsWin() || e.IsLoss() {
+			// deeper search won't change the result
+			break
+		}
+	}
+	return
+}
+
+// extendPV follows the best next states stored in the table
+// after the end of pv, which is cut short when the search
+// takes a result from the table, until pv has the given length.
+func (sr *searcher) extendPV(pv []State, length uint) []State {
+	for len(pv) > 0 && uint(len(pv)) < length {
+		s := pv[len(pv)-1]
+		hash, ok := sr.hash(s)
+		if !ok {
+			break
+		}
+		e, ok := sr.table.probe(hash)
+		if !ok || e.bound != Exact {
+			break
+		}
+		nxt := s.Next()
+		if e.best < 0 || e.best >= len(nxt) {
+			break
+		}
+		pv = append(pv, nxt[e.best])
+	}
+	return pv
+}
+
+// serial runs an iteration of the serial search within (α, β).
+func (sr *searcher) serial(s State, depth uint, findMin bool, α, β Evaluation, pvs bool) (next State, eval Evaluation) {
+	if sr.root != nil {
+		move, eval := sr.moverSearch(sr.root, depth, findMin, α, β, pvs)
+		if move < 0 {
+			return nil, eval
+		}
+		// replay the moves for the states of the principal variation
+		pv, t := sr.pv[0][:0], sr.root
+		for _, mv := range sr.mpv[0] {
+			t = play(t, mv)
+			pv = append(pv, t)
+		}
+		sr.pv[0] = pv
+		return pv[0], eval
+	}
+	if pvs {
+		return sr.negamax(s, depth, findMin, α, β)
+	}
+	return sr.minmax(s, depth, findMin, α, β)
+}
+
+// window returns the aspiration window of the given half width
+// around eval.
+func window(eval, width Evaluation) (α, β Evaluation) {
+	α, β = Lost, Won
+	if eval > Lost+width {
+		α = eval - width
+	}
+	if eval < Won-width {
+		β = eval + width
+	}
+	return
+}
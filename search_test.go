@@ -0,0 +1,90 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// tree is a game where each state has three next states,
+// until the limit of plies is reached.
+type tree struct {
+	v     int // tells the path from the root
+	plies int
+	limit int // negative if the game never ends
+}
+
+func (s *tree) Eval() Evaluation {
+	return Evaluation(s.v*7919%101 - 50)
+}
+
+func (s *tree) Next() (nxt []State) {
+	if s.plies == s.limit {
+		return nil
+	}
+	for k := 0; k < 3; k++ {
+		nxt = append(nxt, &tree{s.v*3 + k, s.plies + 1, s.limit})
+	}
+	return
+}
+
+func TestSearchMaxDepth(t *testing.T) {
+	s := &tree{limit: -1}
+	for depth := uint(1); depth <= 5; depth++ {
+		r, err := Search(context.Background(), s, false, Options{MaxDepth: depth})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Depth != depth {
+			t.Errorf("MaxDepth %d: searched to depth %d", depth, r.Depth)
+		}
+		next, eval := MinMax(s, depth, false)
+		if r.Next.(*tree).v != next.(*tree).v || r.Eval != eval {
+			t.Errorf("MaxDepth %d: got %v, MinMax got %v", depth, r.Eval, eval)
+		}
+	}
+}
+
+func TestSearchComplete(t *testing.T) {
+	// the search stops once the whole tree is searched
+	s := &tree{limit: 4}
+	r, err := Search(context.Background(), s, true, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Depth != 4 {
+		t.Errorf("tree of 4 plies searched to depth %d", r.Depth)
+	}
+	if _, eval := MinMax(s, 4, true); r.Eval != eval {
+		t.Errorf("got %v, MinMax got %v", r.Eval, eval)
+	}
+}
+
+func TestSearchBudget(t *testing.T) {
+	s := &tree{limit: -1}
+	r, err := Search(context.Background(), s, false, Options{Budget: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Depth < 1 || r.Next == nil {
+		t.Fatalf("no iteration completed: depth %d", r.Depth)
+	}
+	if r.Elapsed > time.Second {
+		t.Errorf("budget of 10ms took %v", r.Elapsed)
+	}
+	if _, eval := MinMax(s, r.Depth, false); r.Eval != eval {
+		t.Errorf("depth %d: got %v, MinMax got %v", r.Depth, r.Eval, eval)
+	}
+}
+
+func TestSearchCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r, err := Search(ctx, &tree{limit: -1}, false, Options{})
+	if err != context.Canceled {
+		t.Errorf("cancelled search returned %v", err)
+	}
+	if r.Depth != 0 {
+		t.Errorf("cancelled search completed depth %d", r.Depth)
+	}
+}
@@ -0,0 +1,128 @@
+package game
+
+import "sync/atomic"
+
+// Hasher is implemented by a State that can be stored in a Table.
+type Hasher interface {
+	// Hash returns the hash of the state.
+	// Two states with the same hash are assumed to be the same,
+	// hence to have the same evaluation and the same next states
+	// in the same order.
+	Hash() uint64
+}
+
+// Bound tells how an evaluation stored in a Table
+// relates to the true evaluation of the state.
+type Bound uint8
+
+const (
+	// Exact means the evaluation is exact.
+	Exact Bound = iota
+	// LowerBound means the true evaluation is at least the stored one.
+	LowerBound
+	// UpperBound means the true evaluation is at most the stored one.
+	UpperBound
+)
+
+// unlimited is the depth recorded for a state whose subtree
+// has been searched completely.
+const unlimited = ^uint(0)
+
+// maxStoredDepth is the largest depth that can be stored in a slot
+// other than unlimited; deeper searches are recorded with this depth.
+const maxStoredDepth = 254
+
+// entry is a search result stored in a Table.
+type entry struct {
+	depth uint // depth of the search, or unlimited
+	eval  Evaluation
+	bound Bound
+	best  int // index of the best next state
+}
+
+// slot holds an entry in two words, each loaded and stored atomically,
+// so it can be accessed by concurrent searches without locking.
+// data is the packed entry, and key is the hash of the state XOR'ed
+// with data.  A reader XORs the two words back and compares the result
+// with the hash, so a slot whose words come from different writes
+// does not match any state.
+type slot struct {
+	key, data atomic.Uint64
+}
+
+func (e *entry) pack() (data uint64) {
+	depth := e.depth
+	switch {
+	case depth == unlimited:
+		depth = maxStoredDepth + 1
+	case depth > maxStoredDepth:
+		depth = maxStoredDepth
+	}
+	data = uint64(uint32(e.eval))
+	data |= uint64(depth) << 32
+	data |= uint64(e.bound) << 40
+	data |= uint64(e.best+1) << 42
+	return
+}
+
+func (e *entry) unpack(data uint64) {
+	e.eval = Evaluation(int32(uint32(data)))
+	e.depth = uint(data >> 32 & 0xff)
+	if e.depth > maxStoredDepth {
+		e.depth = unlimited
+	}
+	e.bound = Bound(data >> 40 & 3)
+	e.best = int(data>>42) - 1
+}
+
+// Table is a transposition table, which remembers the results
+// of previous searches, so a state reached in different ways
+// is searched only once.  It is safe for concurrent use.
+//
+// A Table has a fixed number of entries.  A new result replaces
+// the one in its entry, unless both are about the same state
+// and the old one was searched deeper.
+type Table struct {
+	slots []slot
+	mask  uint64
+}
+
+// NewTable returns a Table with 1<<bits entries.
+func NewTable(bits uint) *Table {
+	return &Table{
+		slots: make([]slot, 1<<bits),
+		mask:  1<<bits - 1,
+	}
+}
+
+// Clear removes all entries from the table.
+func (t *Table) Clear() {
+	for i := range t.slots {
+		t.slots[i].key.Store(0)
+		t.slots[i].data.Store(0)
+	}
+}
+
+// probe returns the entry of the state with the given hash.
+// ok is false if it is not in the table.
+func (t *Table) probe(hash uint64) (e entry, ok bool) {
+	s := &t.slots[hash&t.mask]
+	data := s.data.Load()
+	if data == 0 || s.key.Load()^data != hash {
+		return
+	}
+	e.unpack(data)
+	return e, true
+}
+
+// store saves a search result into the table.
+func (t *Table) store(hash uint64, depth uint, eval Evaluation, bound Bound, best int) {
+	s := &t.slots[hash&t.mask]
+	if old, ok := t.probe(hash); ok && old.depth > depth {
+		return
+	}
+	e := entry{depth, eval, bound, best}
+	data := e.pack()
+	s.key.Store(hash ^ data)
+	s.data.Store(data)
+}
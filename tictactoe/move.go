@@ -22,3 +22,8 @@ func (m Move) Valid() bool {
 func (m Move) Allowed(s *State) bool {
 	return s.Board[m.I][m.J] == Empty
 }
+
+// MoveKey returns the index of the cell where the last move was placed.
+func (s *State) MoveKey() int {
+	return int(s.LastMove.I)*N + int(s.LastMove.J)
+}
@@ -0,0 +1,44 @@
+package tictactoe
+
+import "github.com/z-rui/game"
+
+// Moves appends the moves allowed in the current state to moves,
+// in the same order as Next.  A move is the index of the cell, i*N+j.
+func (s *State) Moves(moves []int) []int {
+	if s.IsEnd() {
+		return moves
+	}
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			if s.Board[i][j] == Empty {
+				moves = append(moves, i*N+j)
+			}
+		}
+	}
+	return moves
+}
+
+// Play makes the move in place.
+func (s *State) Play(move int) {
+	s.history = append(s.history, s.LastMove)
+	m := Move{uint8(move / N), uint8(move % N)}
+	s.Board[m.I][m.J] = s.Turn
+	s.LastMove = m
+	s.Turn ^= O ^ X
+}
+
+// Undo takes back the last move played.
+func (s *State) Undo() {
+	s.Board[s.LastMove.I][s.LastMove.J] = Empty
+	n := len(s.history) - 1
+	s.LastMove = s.history[n]
+	s.history = s.history[:n]
+	s.Turn ^= O ^ X
+}
+
+// Clone returns a copy of the current state.
+func (s *State) Clone() game.Mover {
+	t := *s
+	t.history = nil
+	return &t
+}
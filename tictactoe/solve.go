@@ -0,0 +1,96 @@
+package tictactoe
+
+import (
+	"github.com/z-rui/game"
+	"sync"
+)
+
+// position is the key of a state in the database.
+type position struct {
+	board [N][N]Cell
+	turn  Cell
+}
+
+// The database of solved positions, keyed by canonical states.
+// It is computed on first use from the start of the game,
+// and extended with any other position looked up.
+var (
+	dbOnce    sync.Once
+	dbMutex   sync.Mutex
+	values    map[position]game.Evaluation
+	positions int // number of positions reachable from the start
+)
+
+// loadDB solves all the positions reachable from the start.
+func loadDB() {
+	dbOnce.Do(func() {
+		values = make(map[position]game.Evaluation)
+		solve(NewState())
+		for key := range values {
+			positions += orbit(key.board)
+		}
+	})
+}
+
+// orbit returns the number of distinct boards symmetric to b.
+func orbit(b [N][N]Cell) int {
+	s := State{Board: b, LastMove: invalidMove}
+	seen := make(map[[N][N]Cell]bool)
+	for k := Identity; k < Transforms; k++ {
+		seen[s.Transform(k).Board] = true
+	}
+	return len(seen)
+}
+
+// solve returns the value of s, adding it to the database.
+// The caller must hold dbMutex, except in loadDB.
+func solve(s *State) game.Evaluation {
+	c, _ := s.Canonical()
+	key := position{c.Board, c.Turn}
+	if v, ok := values[key]; ok {
+		return v
+	}
+	v := s.Eval()
+	if !s.IsEnd() {
+		findMin := s.Turn == X
+		for i, u := range s.Next() {
+			e := solve(u.(*State)).Later()
+			if i == 0 || findMin && e < v || !findMin && e > v {
+				v = e
+			}
+		}
+	}
+	values[key] = v
+	return v
+}
+
+// Value returns the game-theoretic value of s: the evaluation
+// if both players play perfectly, which is 0 for a draw, and
+// tells in how many plies the game is won or lost otherwise.
+// It is the same as MinMax searching to the end of the game.
+func Value(s *State) game.Evaluation {
+	loadDB()
+	dbMutex.Lock()
+	defer dbMutex.Unlock()
+	return solve(s)
+}
+
+// BestMoves returns all the moves from s that lead to its value,
+// or nil if the game has ended.
+func BestMoves(s *State) (moves []Move) {
+	v := Value(s)
+	for _, u := range s.Next() {
+		t := u.(*State)
+		if Value(t).Later() == v {
+			moves = append(moves, t.LastMove)
+		}
+	}
+	return
+}
+
+// Positions returns the number of positions reachable from the start
+// of the game, counting the symmetric ones separately.
+func Positions() int {
+	loadDB()
+	return positions
+}
@@ -31,7 +31,8 @@ func (c Cell) String() string {
 type State struct {
 	Board    [N][N]Cell
 	LastMove Move
-	Turn     Cell // must be O or X
+	Turn     Cell   // must be O or X
+	history  []Move // last moves before those played by Play
 }
 
 // NewState returns a new state at the start of the game.
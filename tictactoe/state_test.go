@@ -1,7 +1,9 @@
 package tictactoe
 
 import (
+	"context"
 	"github.com/z-rui/game"
+	"math/rand"
 	"testing"
 )
 
@@ -54,3 +56,196 @@ func TestEval(t *testing.T) {
 		t.Errorf("lost game not evaluated Lost: %v", e)
 	}
 }
+
+func TestSearchWithTable(t *testing.T) {
+	s := NewState().Move(Move{1, 1})
+	_, want := game.MinMax(s, N*N, true)
+	r, _ := game.Search(context.Background(), s, true, game.Options{Table: game.NewTable(12)})
+	if got := r.Eval; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSearchPV(t *testing.T) {
+	s := NewState()
+	r, _ := game.Search(context.Background(), s, false, game.Options{})
+	if len(r.PV) == 0 || r.PV[0] != r.Next {
+		t.Fatalf("PV does not start with the best move: %v", r.PV)
+	}
+	for _, u := range r.PV {
+		m := u.(*State).LastMove
+		if s.Move(m) == nil {
+			t.Fatalf("PV contains illegal move %v", m)
+		}
+		s = u.(*State)
+	}
+	if !s.IsEnd() {
+		t.Errorf("PV does not reach the end of the game")
+	}
+	if r.Nodes == 0 || r.Cutoffs == 0 {
+		t.Errorf("statistics not collected: %+v", r)
+	}
+}
+
+func TestMCTS(t *testing.T) {
+	s := NewState()
+	s.Board = [N][N]Cell{
+		{O, O, E},
+		{X, X, E},
+		{E, E, E},
+	}
+	s.LastMove = Move{1, 1}
+	s.Turn = O
+	m := &game.MCTS{Iterations: 2000, Rand: rand.New(rand.NewSource(1))}
+	r, _ := m.Search(context.Background(), s, false)
+	if mv := r.Next.(*State).LastMove; mv != (Move{0, 2}) {
+		t.Errorf("winning move not found: %v", mv)
+	}
+	if r.Eval != game.MCTSScale {
+		t.Errorf("win not evaluated %v: %v", game.MCTSScale, r.Eval)
+	}
+
+	s.Turn = X
+	r, _ = m.Search(context.Background(), s, true)
+	if mv := r.Next.(*State).LastMove; mv != (Move{1, 2}) {
+		t.Errorf("winning move not found: %v", mv)
+	}
+}
+
+func TestQuickWin(t *testing.T) {
+	s := NewState()
+	s.Board = [N][N]Cell{
+		{O, O, E},
+		{X, X, E},
+		{O, X, E},
+	}
+	s.LastMove = Move{1, 1}
+	s.Turn = O
+	next, eval := game.MinMax(s, N*N, false)
+	if mv := next.(*State).LastMove; mv != (Move{0, 2}) {
+		t.Errorf("quickest win not chosen: %v", mv)
+	}
+	if eval != game.WinIn(1) || eval.PliesToWin() != 1 {
+		t.Errorf("win in 1 not evaluated: %v", eval)
+	}
+
+	s.Turn = X
+	s.Board[0][0] = E
+	next, eval = game.MinMax(s, N*N, true)
+	if mv := next.(*State).LastMove; mv != (Move{1, 2}) {
+		t.Errorf("quickest win not chosen: %v", mv)
+	}
+	if !eval.IsLoss() || eval.PliesToLoss() != 1 {
+		t.Errorf("win in 1 for X not evaluated: %v", eval)
+	}
+}
+
+func TestPerft(t *testing.T) {
+	for depth, want := range []uint64{
+		1, 9, 72, 504, 3024, 15120, 54720, 148176, 200448, 127872,
+	} {
+		if got := game.Perft(NewState(), uint(depth)); got != want {
+			t.Errorf("depth %d: got %d, want %d", depth, got, want)
+		}
+	}
+}
+
+// noMover hides the Mover methods of a State,
+// so that it is searched through Next.
+type noMover struct {
+	*State
+	Moves, Play, Undo, Clone struct{}
+}
+
+func TestMover(t *testing.T) {
+	s := NewState()
+	for depth := uint(0); depth <= N*N; depth++ {
+		if got, want := game.Perft(s, depth), game.Perft(noMover{State: s}, depth); got != want {
+			t.Errorf("depth %d: got %d, want %d", depth, got, want)
+		}
+	}
+	_, want := game.MinMax(s, N*N, false)
+	r, _ := game.Search(context.Background(), s, false, game.Options{Table: game.NewTable(12), PVS: true})
+	if r.Eval != want {
+		t.Errorf("got %v, want %v", r.Eval, want)
+	}
+	if len(s.history) != 0 || s.LastMove != invalidMove {
+		t.Errorf("search changed the root")
+	}
+}
+
+func TestTyped(t *testing.T) {
+	s := NewState().Move(Move{1, 1})
+	next, want := game.MinMaxOf(s, N*N, true)
+	r, _ := game.SearchOf(context.Background(), s, true, game.Options{Table: game.NewTable(12)})
+	if r.Eval != want {
+		t.Errorf("got %v, want %v", r.Eval, want)
+	}
+	if r.Next.LastMove != next.LastMove || r.PV[0] != r.Next {
+		t.Errorf("got %v, want %v", r.Next.LastMove, next.LastMove)
+	}
+	if end, _ := game.MinMaxOf(r.PV[len(r.PV)-1], 1, false); end != nil {
+		t.Errorf("PV does not reach the end of the game")
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	// all legal positions, and those up to symmetry
+	seen := make(map[[N][N]Cell]bool)
+	canonical := make(map[[N][N]Cell]bool)
+	var walk func(s *State)
+	walk = func(s *State) {
+		if seen[s.Board] {
+			return
+		}
+		seen[s.Board] = true
+		c, k := s.Canonical()
+		canonical[c.Board] = true
+		if back := c.Transform(k.Inverse()); back.Board != s.Board || back.Eval() != s.Eval() {
+			t.Fatalf("canonical transform of %v does not map back", s.Board)
+		}
+		for _, u := range s.Next() {
+			walk(u.(*State))
+		}
+	}
+	walk(NewState())
+	if len(seen) != 5478 || len(canonical) != 765 {
+		t.Errorf("got %d positions, %d up to symmetry; want 5478, 765",
+			len(seen), len(canonical))
+	}
+}
+
+func TestSolve(t *testing.T) {
+	s := NewState()
+	if v := Value(s); v != 0 {
+		t.Errorf("initial position: got %v, want a draw", v)
+	}
+	if got := BestMoves(s); len(got) != N*N {
+		t.Errorf("initial position: got best moves %v, want all", got)
+	}
+	if n := Positions(); n != 5478 {
+		t.Errorf("got %d reachable positions, want 5478", n)
+	}
+	if n := len(values); n != 765 {
+		t.Errorf("got %d positions up to symmetry, want 765", n)
+	}
+
+	// X must take the center against a corner
+	s = s.Move(Move{0, 0})
+	if got := BestMoves(s); len(got) != 1 || got[0] != (Move{1, 1}) {
+		t.Errorf("after A1: got best moves %v, want [B2]", got)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		s := NewState()
+		for k := r.Intn(6); k > 0 && !s.IsEnd(); k-- {
+			nxt := s.Next()
+			s = nxt[r.Intn(len(nxt))].(*State)
+		}
+		_, want := game.MinMax(s, N*N, s.Turn == X)
+		if got := Value(s); got != want {
+			t.Errorf("%v: got %v, want %v", s.Board, got, want)
+		}
+	}
+}
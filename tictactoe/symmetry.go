@@ -0,0 +1,78 @@
+package tictactoe
+
+// Transform is one of the 8 symmetries of the board:
+// it reflects the rows if the bit 1 is set, the columns if the bit 2
+// is set, and then transposes the board if the bit 4 is set.
+type Transform uint8
+
+// Identity is the transform that changes nothing.
+const Identity Transform = 0
+
+// Transforms is the number of transforms.
+const Transforms = 8
+
+// Move maps m through the transform.  An invalid move stays invalid.
+func (t Transform) Move(m Move) Move {
+	if !m.Valid() {
+		return m
+	}
+	const last = N - 1
+	if t&1 != 0 {
+		m.I = last - m.I
+	}
+	if t&2 != 0 {
+		m.J = last - m.J
+	}
+	if t&4 != 0 {
+		m.I, m.J = m.J, m.I
+	}
+	return m
+}
+
+// Inverse returns the transform that undoes t.
+func (t Transform) Inverse() Transform {
+	if t&4 == 0 {
+		return t
+	}
+	// the reflections swap places across the transposition
+	return 4 | t&1<<1 | t&2>>1
+}
+
+// Transform returns the state with the board and the last move
+// mapped through t.
+func (s *State) Transform(t Transform) *State {
+	u := &State{LastMove: t.Move(s.LastMove), Turn: s.Turn}
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			m := t.Move(Move{uint8(i), uint8(j)})
+			u.Board[m.I][m.J] = s.Board[i][j]
+		}
+	}
+	return u
+}
+
+// Canonical returns the representative of the states symmetric to s,
+// which is the same for all of them, and the transform that maps s
+// to it.  The representative has the least board in the row-major
+// order; the last move is not considered.
+func (s *State) Canonical() (c *State, t Transform) {
+	c, t = s.Transform(Identity), Identity
+	for k := Identity + 1; k < Transforms; k++ {
+		if u := s.Transform(k); less(&u.Board, &c.Board) {
+			c, t = u, k
+		}
+	}
+	return
+}
+
+// less compares two boards in the row-major order.
+func less(a, b *[N][N]Cell) bool {
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			if a[i][j] != b[i][j] {
+				return a[i][j] < b[i][j]
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package game
+
+import "context"
+
+// TypedResult is a Result whose states are of the concrete type S,
+// so that they need no type assertion.
+type TypedResult[S State] struct {
+	*Result
+	// Next is the best next state, or the zero value if there is none.
+	Next S
+	// PV is the principal variation, which starts with Next.
+	PV []S
+}
+
+// ResultOf converts r to a TypedResult.
+// The states in r must be of type S.
+func ResultOf[S State](r *Result) *TypedResult[S] {
+	t := &TypedResult[S]{Result: r}
+	if r.Next != nil {
+		t.Next = r.Next.(S)
+	}
+	t.PV = make([]S, len(r.PV))
+	for i, u := range r.PV {
+		t.PV[i] = u.(S)
+	}
+	return t
+}
+
+// MinMaxOf is MinMax for states of type S,
+// whose next states must be of type S as well.
+func MinMaxOf[S State](s S, iterations uint, findMin bool) (next S, eval Evaluation) {
+	n, eval := MinMax(s, iterations, findMin)
+	if n != nil {
+		next = n.(S)
+	}
+	return
+}
+
+// SearchOf is Search for states of type S,
+// whose next states must be of type S as well.
+func SearchOf[S State](ctx context.Context, s S, findMin bool, opt Options) (*TypedResult[S], error) {
+	r, err := Search(ctx, s, findMin, opt)
+	return ResultOf[S](r), err
+}